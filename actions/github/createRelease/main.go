@@ -11,11 +11,12 @@ import (
 
     "github.com/EncoreDigitalGroup/golib/logger"
     "github.com/google/go-github/v70/github"
-    "golang.org/x/oauth2"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/auth"
 )
 
 func main() {
-    githubToken := getEnv("GH_TOKEN")
     repo := getEnv("GH_REPOSITORY")
     tagName := getEnv("TAG_NAME")
     preReleaseStr := getEnv("PRE_RELEASE")
@@ -35,11 +36,16 @@ func main() {
     repoOwner := repoParts[0]
     repoName := repoParts[1]
 
-    // Initialize GitHub client
+    // Initialize GitHub client with a token, or a GitHub App installation token for the repository, against the API the workflow runs on
     ctx := context.Background()
-    ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
-    tc := oauth2.NewClient(ctx, ts)
-    client := github.NewClient(tc)
+    ts, err := auth.TokenSource(ctx, repoOwner, repoName)
+    if err != nil {
+        log.Fatal(err)
+    }
+    client, err := api.NewClient(ts)
+    if err != nil {
+        log.Fatal(err)
+    }
 
     // Create the release
     release := &github.RepositoryRelease{
@@ -0,0 +1,229 @@
+package azureboards
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const Name = "azure-boards"
+
+const envOrganizationURL = "OPT_AZURE_BOARDS_ORG_URL"
+const envToken = "OPT_AZURE_BOARDS_TOKEN"
+
+const apiVersion = "7.1"
+
+var regexWorkItem = regexp.MustCompile(`(?i)(?:^|[/_-])AB[#-]?([0-9]+)(?:-|$)`)
+
+type Configuration struct {
+    OrganizationURL string
+    Token           string
+    WorkItemID      int
+}
+
+type Information struct {
+    ParentPrefix string
+    Title        string
+    Description  string
+    WorkItemType string
+}
+
+type workItem struct {
+    ID     int `json:"id"`
+    Fields struct {
+        Title        string `json:"System.Title"`
+        Description  string `json:"System.Description"`
+        WorkItemType string `json:"System.WorkItemType"`
+        Parent       int    `json:"System.Parent"`
+    } `json:"fields"`
+}
+
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    var missing []string
+    for _, envVar := range []string{envOrganizationURL, envToken} {
+        if os.Getenv(envVar) == "" {
+            missing = append(missing, envVar)
+        }
+    }
+
+    if len(missing) > 0 {
+        return fmt.Errorf("%s must be set when configured strategy is '%s'", strings.Join(missing, ", "), Name)
+    }
+
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    options := syncOptions()
+    if synced, err := options.AlreadySynced(ctx, gh); err != nil || synced {
+        return err
+    }
+
+    branchName, err := gh.GetBranchName(ctx)
+    if err != nil {
+        return err
+    }
+
+    workItemID := GetWorkItemIDFromBranchName(branchName)
+    if workItemID == 0 {
+        return fmt.Errorf("%w: branch %s does not reference an Azure Boards work item", drivers.ErrNotFound, branchName)
+    }
+
+    config := Configuration{
+        OrganizationURL: os.Getenv(envOrganizationURL),
+        Token:           os.Getenv(envToken),
+        WorkItemID:      workItemID,
+    }
+
+    azure, err := getAzureInfo(ctx, config)
+    if err != nil {
+        return drivers.FetchFailed(ctx, gh, drivers.Tracker{
+            Name: "Azure Boards",
+            Item: "work item",
+            Solutions: []string{
+                "Check that `" + envOrganizationURL + "` and `" + envToken + "` environment variables are set correctly",
+                "Verify that the Personal Access Token has the `Work Items (Read)` scope",
+                "Ensure the token owner has permission to access the work item: `" + workItemKey(workItemID) + "`",
+            },
+        }, workItemKey(workItemID), err)
+    }
+
+    return drivers.Sync(ctx, gh, drivers.Issue{
+        Key:          workItemKey(workItemID),
+        Title:        azure.Title,
+        Description:  azure.Description,
+        ParentPrefix: azure.ParentPrefix,
+        Type:         azure.WorkItemType,
+    }, options)
+}
+
+// GetWorkItemIDFromBranchName returns the work item ID referenced by branches like feature/AB#1234-add-export, or 0 if there is none
+func GetWorkItemIDFromBranchName(branchName string) int {
+    matches := regexWorkItem.FindStringSubmatch(branchName)
+    if matches == nil {
+        return 0
+    }
+
+    workItemID, err := strconv.Atoi(matches[1])
+    if err != nil {
+        return 0
+    }
+
+    return workItemID
+}
+
+func workItemKey(workItemID int) string {
+    return fmt.Sprintf("AB#%d", workItemID)
+}
+
+func getWorkItem(ctx context.Context, config Configuration, workItemID int, fields ...string) (*workItem, error) {
+    url := fmt.Sprintf("%s/_apis/wit/workitems/%d?api-version=%s", strings.TrimSuffix(config.OrganizationURL, "/"), workItemID, apiVersion)
+    if len(fields) > 0 {
+        url += "&fields=" + strings.Join(fields, ",")
+    }
+
+    request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        return nil, err
+    }
+
+    request.Header.Set("Accept", "application/json")
+    request.SetBasicAuth("", config.Token)
+
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch work item %s: %v", workItemKey(workItemID), err)
+    }
+    defer response.Body.Close()
+
+    // Azure DevOps answers an invalid PAT with a 203 and an HTML sign-in page instead of a 401
+    if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusNonAuthoritativeInfo {
+        return nil, &drivers.TrackerError{
+            IsAuthFailure: true,
+            OriginalError: fmt.Errorf("failed to fetch work item %s: %s", workItemKey(workItemID), response.Status),
+        }
+    }
+
+    if response.StatusCode != http.StatusOK {
+        return nil, &drivers.TrackerError{
+            IsNotFound:    response.StatusCode == http.StatusNotFound,
+            OriginalError: fmt.Errorf("failed to fetch work item %s: %s", workItemKey(workItemID), response.Status),
+        }
+    }
+
+    var result workItem
+    if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+        return nil, fmt.Errorf("failed to decode work item %s: %v", workItemKey(workItemID), err)
+    }
+
+    return &result, nil
+}
+
+func getParentPrefix(ctx context.Context, config Configuration, parentID int) (string, error) {
+    if parentID == 0 {
+        return "", nil
+    }
+
+    parent, err := getWorkItem(ctx, config, parentID, "System.WorkItemType")
+    if err != nil {
+        return "", err
+    }
+
+    if strings.ToLower(parent.Fields.WorkItemType) == "epic" {
+        return "", nil
+    }
+
+    return workItemKey(parentID), nil
+}
+
+func getAzureInfo(ctx context.Context, config Configuration) (Information, error) {
+    item, err := getWorkItem(ctx, config, config.WorkItemID)
+    if err != nil {
+        return Information{}, err
+    }
+
+    result := Information{
+        Title:        item.Fields.Title,
+        Description:  item.Fields.Description,
+        WorkItemType: item.Fields.WorkItemType,
+    }
+
+    // Get parent work item prefix if applicable
+    parentPrefix, err := getParentPrefix(ctx, config, item.Fields.Parent)
+    if err != nil {
+        logger.Errorf("Failed to get parent work item info: %v", err)
+        // Don't fail completely, just continue without parent prefix
+    } else {
+        result.ParentPrefix = parentPrefix
+    }
+
+    return result, nil
+}
+
+func syncOptions() drivers.SyncOptions {
+    return drivers.NewSyncOptions("Azure Boards", "AZURE_BOARDS", "azure-boards-sync-complete")
+}
@@ -0,0 +1,113 @@
+package azureboards
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+)
+
+func TestGetWorkItemIDFromBranchName(t *testing.T) {
+    tests := []struct {
+        branchName string
+        expected   int
+    }{
+        {branchName: "feature/AB#1234-add-export", expected: 1234},
+        {branchName: "AB#42-fix-login", expected: 42},
+        {branchName: "bugfix/ab-77-fix-login", expected: 77},
+        {branchName: "feature/TAB#12-something", expected: 0},
+        {branchName: "feature/PROJ-123-add-export", expected: 0},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            if actual := GetWorkItemIDFromBranchName(tt.branchName); actual != tt.expected {
+                t.Errorf("GetWorkItemIDFromBranchName() = %d, want %d", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestGetAzureInfo(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if _, token, ok := r.BasicAuth(); !ok || token != "test-pat" {
+            w.WriteHeader(http.StatusNonAuthoritativeInfo)
+            w.Write([]byte("<html>Sign In</html>"))
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        switch r.URL.Path {
+        case "/contoso/_apis/wit/workitems/1234":
+            w.Write([]byte(`{"id":1234,"fields":{"System.Title":"Add export endpoint","System.Description":"<p>Export all records</p>","System.WorkItemType":"User Story","System.Parent":1200}}`))
+        case "/contoso/_apis/wit/workitems/1200":
+            w.Write([]byte(`{"id":1200,"fields":{"System.WorkItemType":"Feature"}}`))
+        case "/contoso/_apis/wit/workitems/1300":
+            w.Write([]byte(`{"id":1300,"fields":{"System.Title":"Child of epic","System.WorkItemType":"Task","System.Parent":1000}}`))
+        case "/contoso/_apis/wit/workitems/1000":
+            w.Write([]byte(`{"id":1000,"fields":{"System.WorkItemType":"Epic"}}`))
+        default:
+            w.WriteHeader(http.StatusNotFound)
+        }
+    }))
+    defer server.Close()
+
+    organizationURL := server.URL + "/contoso"
+
+    tests := []struct {
+        name            string
+        config          Configuration
+        expected        Information
+        wantNotFound    bool
+        wantAuthFailure bool
+    }{
+        {
+            name:   "work item with feature parent",
+            config: Configuration{OrganizationURL: organizationURL, Token: "test-pat", WorkItemID: 1234},
+            expected: Information{
+                ParentPrefix: "AB#1200",
+                Title:        "Add export endpoint",
+                Description:  "<p>Export all records</p>",
+                WorkItemType: "User Story",
+            },
+        },
+        {
+            name:     "epic parent is not used as prefix",
+            config:   Configuration{OrganizationURL: organizationURL, Token: "test-pat", WorkItemID: 1300},
+            expected: Information{Title: "Child of epic", WorkItemType: "Task"},
+        },
+        {
+            name:         "missing work item",
+            config:       Configuration{OrganizationURL: organizationURL, Token: "test-pat", WorkItemID: 9999},
+            wantNotFound: true,
+        },
+        {
+            name:            "invalid token",
+            config:          Configuration{OrganizationURL: organizationURL, Token: "expired", WorkItemID: 1234},
+            wantAuthFailure: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            info, err := getAzureInfo(context.Background(), tt.config)
+
+            var trackerErr *drivers.TrackerError
+            if errors.As(err, &trackerErr) != (tt.wantNotFound || tt.wantAuthFailure) {
+                t.Fatalf("getAzureInfo() error = %v", err)
+            }
+
+            if trackerErr != nil && (trackerErr.IsNotFound != tt.wantNotFound || trackerErr.IsAuthFailure != tt.wantAuthFailure) {
+                t.Errorf("getAzureInfo() error = %+v, wantNotFound %v, wantAuthFailure %v", trackerErr, tt.wantNotFound, tt.wantAuthFailure)
+            }
+
+            if !reflect.DeepEqual(info, tt.expected) {
+                t.Errorf("getAzureInfo() = %+v, want %+v", info, tt.expected)
+            }
+        })
+    }
+}
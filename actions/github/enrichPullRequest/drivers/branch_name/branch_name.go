@@ -1,37 +1,64 @@
 package branchname
 
 import (
+    "context"
     "fmt"
-    "os"
-    "regexp"
 
     "github.com/EncoreDigitalGroup/golib/logger"
 
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/branch"
     "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
 )
 
-var regexWithIssueType = regexp.MustCompile(`^(epic|feature|bugfix|hotfix)/([A-Z]+-[0-9]+)-(.+)$`)
-var regexWithoutIssueType = regexp.MustCompile(`^([A-Z]+-[0-9]+)-(.+)$`)
+const Name = "branch-name"
+
 var pullRequestTitle string
 
-func Format(gh github.GitHub) {
-    branchName, err := gh.GetBranchName()
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    branchName, err := gh.GetBranchName(ctx)
     if err != nil {
-        logger.Error(err.Error())
-        os.Exit(1)
+        return err
     }
 
-    if !gh.BranchNameMatchesPRTitle(branchName) {
-        formattedTitle := formatTitle(gh, branchName)
-        gh.UpdatePRTitle(formattedTitle)
+    if issueKey, _ := GetIssueKeyFromBranchName(branchName); issueKey == "" {
+        return fmt.Errorf("%w: branch %s does not contain an issue key", drivers.ErrNotFound, branchName)
     }
+
+    matches, err := gh.BranchNameMatchesPRTitle(ctx, branchName)
+    if err != nil || matches {
+        return err
+    }
+
+    syncTitle, err := gh.TitleSyncAllowed(ctx)
+    if err != nil || !syncTitle {
+        return err
+    }
+
+    return gh.UpdatePRTitle(ctx, formatTitle(ctx, gh, branchName))
 }
 
 func GetIssueKeyFromBranchName(branchName string) (string, error) {
-    if matches := regexWithIssueType.FindStringSubmatch(branchName); matches != nil {
-        return matches[2], nil
-    } else if matches := regexWithoutIssueType.FindStringSubmatch(branchName); matches != nil {
-        return matches[1], nil
+    if match, ok := branch.Parse(branchName); ok {
+        return match.Key, nil
     } else {
         fmt.Println("Title does not match expected format")
         logger.Info(pullRequestTitle)
@@ -40,10 +67,8 @@ func GetIssueKeyFromBranchName(branchName string) (string, error) {
 }
 
 func GetIssueNameFromBranchName(branchName string) (string, error) {
-    if matches := regexWithIssueType.FindStringSubmatch(branchName); matches != nil {
-        return matches[3], nil
-    } else if matches := regexWithoutIssueType.FindStringSubmatch(branchName); matches != nil {
-        return matches[2], nil
+    if match, ok := branch.Parse(branchName); ok {
+        return match.Name, nil
     } else {
         fmt.Println("Title does not match expected format")
         logger.Info(pullRequestTitle)
@@ -51,7 +76,7 @@ func GetIssueNameFromBranchName(branchName string) (string, error) {
     }
 }
 
-func formatTitle(gh github.GitHub, branchName string) string {
+func formatTitle(ctx context.Context, gh github.GitHub, branchName string) string {
     issueKey, err := GetIssueKeyFromBranchName(branchName)
     issueName, err := GetIssueNameFromBranchName(branchName)
 
@@ -61,5 +86,15 @@ func formatTitle(gh github.GitHub, branchName string) string {
         return pullRequestTitle
     }
 
-    return gh.ApplyFormatting(issueKey, issueName)
+    // Without multiple issues, keys after the first are just part of the name
+    var relatedKeys []string
+    if drivers.MultipleIssuesEnabled() {
+        relatedKeys, issueName = branch.RelatedKeys(issueName)
+    }
+
+    return gh.ApplyFormatting(ctx, github.TitleData{
+        IssueKey:    issueKey,
+        RelatedKeys: relatedKeys,
+        IssueName:   issueName,
+    })
 }
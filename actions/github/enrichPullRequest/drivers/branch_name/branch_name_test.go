@@ -0,0 +1,53 @@
+package branchname
+
+import (
+    "context"
+    "reflect"
+    "testing"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+// fakeGitHub records the title data formatTitle asks to have formatted
+type fakeGitHub struct {
+    github.GitHub
+    titleData github.TitleData
+}
+
+func (gh *fakeGitHub) ApplyFormatting(ctx context.Context, title github.TitleData) string {
+    gh.titleData = title
+    return ""
+}
+
+func TestFormatTitle(t *testing.T) {
+    tests := []struct {
+        name           string
+        multipleIssues string
+        expected       github.TitleData
+    }{
+        {
+            name:           "multiple issues enabled",
+            multipleIssues: "true",
+            expected:       github.TitleData{IssueKey: "PROJ-12", RelatedKeys: []string{"PROJ-15"}, IssueName: "shared-fix"},
+        },
+        {
+            name:           "multiple issues disabled",
+            multipleIssues: "false",
+            expected:       github.TitleData{IssueKey: "PROJ-12", IssueName: "PROJ-15-shared-fix"},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(drivers.EnvMultipleIssues, tt.multipleIssues)
+
+            gh := &fakeGitHub{}
+            formatTitle(context.Background(), gh, "feature/PROJ-12-PROJ-15-shared-fix")
+
+            if !reflect.DeepEqual(gh.titleData, tt.expected) {
+                t.Errorf("formatTitle() formatted %+v, want %+v", gh.titleData, tt.expected)
+            }
+        })
+    }
+}
@@ -0,0 +1,59 @@
+package drivers
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+// ErrNotFound is returned by a driver when it has no issue to enrich the pull request with,
+// allowing the next driver in the chain to try instead
+var ErrNotFound = errors.New("no matching issue found")
+
+// Chain resolves a comma-separated, ordered list of strategy names to their drivers
+func Chain(strategies string) ([]Driver, error) {
+    var chain []Driver
+    for _, name := range strings.Split(strategies, ",") {
+        name = strings.TrimSpace(name)
+        if name == "" {
+            continue
+        }
+
+        driver, err := Get(name)
+        if err != nil {
+            return nil, err
+        }
+
+        chain = append(chain, driver)
+    }
+
+    if len(chain) == 0 {
+        return nil, fmt.Errorf("no strategy configured; registered drivers: %s", strings.Join(Names(), ", "))
+    }
+
+    return chain, nil
+}
+
+// Run tries each driver in order and returns the name of the first one that enriched the pull request.
+// A driver reporting ErrNotFound falls through to the next driver; any other error stops the chain.
+func Run(ctx context.Context, gh github.GitHub, chain []Driver) (string, error) {
+    for _, driver := range chain {
+        err := driver.Enrich(ctx, gh)
+        if err == nil {
+            return driver.Name(), nil
+        }
+
+        if !errors.Is(err, ErrNotFound) {
+            return "", fmt.Errorf("strategy %s failed: %w", driver.Name(), err)
+        }
+
+        logger.Infof("Strategy %s did not enrich the pull request: %v", driver.Name(), err)
+    }
+
+    return "", ErrNotFound
+}
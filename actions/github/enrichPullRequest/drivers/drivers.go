@@ -1,19 +1,50 @@
 package drivers
 
-const BranchName = "branch-name"
-const Jira = "jira"
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
 
-func Validate(driver string) bool {
-    validDrivers := []string{
-        BranchName,
-        Jira,
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+// Driver enriches a pull request using information from a project management system
+type Driver interface {
+    Name() string
+    Validate() error
+    Enrich(ctx context.Context, gh github.GitHub) error
+}
+
+var registry = map[string]Driver{}
+
+// Register makes a driver available as a strategy. Drivers call this from init.
+func Register(driver Driver) {
+    if _, exists := registry[driver.Name()]; exists {
+        panic("drivers: Register called twice for driver " + driver.Name())
     }
 
-    for _, validDriver := range validDrivers {
-        if driver == validDriver {
-            return true
-        }
+    registry[driver.Name()] = driver
+}
+
+// Get returns the driver registered under the given strategy name
+func Get(name string) (Driver, error) {
+    driver, ok := registry[name]
+    if !ok {
+        return nil, fmt.Errorf("invalid strategy %q; registered drivers: %s", name, strings.Join(Names(), ", "))
     }
 
-    return false
+    return driver, nil
+}
+
+// Names returns the sorted names of all registered drivers
+func Names() []string {
+    names := make([]string, 0, len(registry))
+    for name := range registry {
+        names = append(names, name)
+    }
+
+    sort.Strings(names)
+
+    return names
 }
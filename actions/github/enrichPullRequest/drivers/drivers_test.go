@@ -0,0 +1,227 @@
+package drivers
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net/http"
+    "strings"
+    "testing"
+
+    gogithub "github.com/google/go-github/v70/github"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+type fakeDriver struct {
+    name      string
+    enrichErr error
+}
+
+func (d fakeDriver) Name() string {
+    return d.name
+}
+
+func (d fakeDriver) Validate() error {
+    return nil
+}
+
+func (d fakeDriver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return d.enrichErr
+}
+
+func TestRegistry(t *testing.T) {
+    Register(fakeDriver{name: "test-registered"})
+
+    if driver, err := Get("test-registered"); err != nil || driver.Name() != "test-registered" {
+        t.Errorf("Get() = %v, %v, want the registered driver", driver, err)
+    }
+
+    if _, err := Get("trello"); err == nil || !strings.Contains(err.Error(), "registered drivers: ") || !strings.Contains(err.Error(), "test-registered") {
+        t.Errorf("Get() error = %v, want error listing the registered drivers", err)
+    }
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+    Register(fakeDriver{name: "test-duplicate"})
+
+    defer func() {
+        if recover() == nil {
+            t.Error("Register() should panic when a driver name is registered twice")
+        }
+    }()
+
+    Register(fakeDriver{name: "test-duplicate"})
+}
+
+func TestChain(t *testing.T) {
+    Register(fakeDriver{name: "test-chain-first"})
+    Register(fakeDriver{name: "test-chain-second"})
+
+    chain, err := Chain(" test-chain-first, test-chain-second ,")
+    if err != nil {
+        t.Fatalf("Chain() error = %v", err)
+    }
+
+    if len(chain) != 2 || chain[0].Name() != "test-chain-first" || chain[1].Name() != "test-chain-second" {
+        t.Errorf("Chain() returned drivers in unexpected order: %v", chain)
+    }
+
+    if _, err := Chain("test-chain-first,trello"); err == nil || !strings.Contains(err.Error(), "trello") {
+        t.Errorf("Chain() error = %v, want error naming the unknown strategy", err)
+    }
+}
+
+func TestRun(t *testing.T) {
+    notFound := fakeDriver{name: "not-found", enrichErr: fmt.Errorf("%w: no key in branch", ErrNotFound)}
+    hardError := fakeDriver{name: "hard-error", enrichErr: errors.New("authentication failed")}
+    found := fakeDriver{name: "found"}
+
+    tests := []struct {
+        name       string
+        chain      []Driver
+        enrichedBy string
+        wantErr    error
+    }{
+        {
+            name:       "falls back to the next driver when not found",
+            chain:      []Driver{notFound, found},
+            enrichedBy: "found",
+        },
+        {
+            name:       "first successful driver wins",
+            chain:      []Driver{found, hardError},
+            enrichedBy: "found",
+        },
+        {
+            name:    "hard error stops the chain",
+            chain:   []Driver{hardError, found},
+            wantErr: hardError.enrichErr,
+        },
+        {
+            name:    "no driver finds an issue",
+            chain:   []Driver{notFound, notFound},
+            wantErr: ErrNotFound,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            enrichedBy, err := Run(context.Background(), nil, tt.chain)
+
+            if tt.wantErr != nil {
+                if !errors.Is(err, tt.wantErr) {
+                    t.Fatalf("Run() error = %v, want %v", err, tt.wantErr)
+                }
+                return
+            }
+
+            if err != nil {
+                t.Fatalf("Run() error = %v", err)
+            }
+
+            if enrichedBy != tt.enrichedBy {
+                t.Errorf("Run() = %s, want %s", enrichedBy, tt.enrichedBy)
+            }
+        })
+    }
+}
+
+// fakeGitHub records the label and comment calls ApplyLabel makes; calling any other method panics
+type fakeGitHub struct {
+    github.GitHub
+    createErr  error
+    addErr     error
+    commentErr error
+    labels     []string
+    comments   []string
+}
+
+func (f *fakeGitHub) EnsureLabelExists(ctx context.Context, labelName string, description string, color string) error {
+    return f.createErr
+}
+
+func (f *fakeGitHub) AddLabelToPR(ctx context.Context, labelName string) error {
+    if f.addErr == nil {
+        f.labels = append(f.labels, labelName)
+    }
+
+    return f.addErr
+}
+
+func (f *fakeGitHub) AddPRComment(ctx context.Context, comment string) error {
+    if f.commentErr == nil {
+        f.comments = append(f.comments, comment)
+    }
+
+    return f.commentErr
+}
+
+func apiError(status int) error {
+    return &gogithub.ErrorResponse{Response: &http.Response{StatusCode: status, Request: &http.Request{}}}
+}
+
+func TestApplyLabel(t *testing.T) {
+    tests := []struct {
+        name             string
+        gh               *fakeGitHub
+        expectedLabels   int
+        expectedComments int
+        wantErr          bool
+    }{
+        {name: "label applied", gh: &fakeGitHub{}, expectedLabels: 1},
+        {name: "refused label is explained in a comment", gh: &fakeGitHub{createErr: apiError(http.StatusForbidden)}, expectedComments: 1},
+        {name: "refused add is explained in a comment", gh: &fakeGitHub{addErr: apiError(http.StatusForbidden)}, expectedComments: 1},
+        {name: "exhausted retries fail without a comment", gh: &fakeGitHub{addErr: apiError(http.StatusBadGateway)}, wantErr: true},
+        {name: "comment that cannot be posted fails", gh: &fakeGitHub{createErr: apiError(http.StatusForbidden), commentErr: apiError(http.StatusForbidden)}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := ApplyLabel(context.Background(), tt.gh, "jira-sync-complete", "Synced", "0052cc")
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("ApplyLabel() error = %v, wantErr %v", err, tt.wantErr)
+            }
+
+            if len(tt.gh.labels) != tt.expectedLabels || len(tt.gh.comments) != tt.expectedComments {
+                t.Errorf("ApplyLabel() added %d labels and %d comments, want %d and %d", len(tt.gh.labels), len(tt.gh.comments), tt.expectedLabels, tt.expectedComments)
+            }
+        })
+    }
+}
+
+func TestFetchFailed(t *testing.T) {
+    tracker := Tracker{Name: "Linear", Item: "issue", Solutions: []string{"Check the token"}}
+    tests := []struct {
+        name             string
+        err              error
+        expectedNotFound bool
+        expectedComment  string
+    }{
+        {name: "missing issue", err: &TrackerError{IsNotFound: true, OriginalError: errors.New("not found")}, expectedNotFound: true},
+        {name: "authentication failure", err: &TrackerError{IsAuthFailure: true, OriginalError: errors.New("401 Unauthorized")}, expectedComment: "**Linear Authentication Failed**"},
+        {name: "other failure", err: errors.New("connection refused"), expectedComment: "Failed to get information from Linear."},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            gh := &fakeGitHub{}
+            err := FetchFailed(context.Background(), gh, tracker, "ENG-1", tt.err)
+            if err == nil || errors.Is(err, ErrNotFound) != tt.expectedNotFound {
+                t.Fatalf("FetchFailed() error = %v, want ErrNotFound %v", err, tt.expectedNotFound)
+            }
+
+            if tt.expectedComment == "" {
+                if len(gh.comments) != 0 {
+                    t.Errorf("FetchFailed() commented %q on a missing issue", gh.comments)
+                }
+
+                return
+            }
+
+            if len(gh.comments) != 1 || !strings.HasPrefix(gh.comments[0], tt.expectedComment) {
+                t.Errorf("FetchFailed() comments = %q, want one starting %q", gh.comments, tt.expectedComment)
+            }
+        })
+    }
+}
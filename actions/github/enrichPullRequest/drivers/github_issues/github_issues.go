@@ -0,0 +1,107 @@
+package githubissues
+
+import (
+    "context"
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const Name = "github-issues"
+
+var regexIssueNumber = regexp.MustCompile(`^(?:[A-Za-z]+/)?#?([0-9]+)-(.+)$`)
+
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    options := syncOptions()
+    if synced, err := options.AlreadySynced(ctx, gh); err != nil || synced {
+        return err
+    }
+
+    branchName, err := gh.GetBranchName(ctx)
+    if err != nil {
+        return err
+    }
+
+    issueNumber := GetIssueNumberFromBranchName(branchName)
+    if issueNumber == 0 {
+        return fmt.Errorf("%w: branch %s does not reference a GitHub issue number", drivers.ErrNotFound, branchName)
+    }
+
+    issue, err := gh.GetIssue(ctx, issueNumber)
+    if github.IsNotFound(err) {
+        return fmt.Errorf("%w: GitHub issue #%d does not exist", drivers.ErrNotFound, issueNumber)
+    }
+
+    if err != nil {
+        logger.Errorf("Failed to get GitHub issue info: %v", err)
+        comment := fmt.Sprintf("Failed to get information from GitHub issue #%d.\n\n", issueNumber) +
+            "Please check the GitHub Action logs for specific error information."
+
+        return drivers.Comment(ctx, gh, err, comment)
+    }
+
+    if issue.IsPullRequest() {
+        return fmt.Errorf("%w: #%d is a pull request, not an issue", drivers.ErrNotFound, issueNumber)
+    }
+
+    return drivers.Sync(ctx, gh, drivers.Issue{
+        Key:         fmt.Sprintf("#%d", issueNumber),
+        Title:       issue.GetTitle(),
+        Description: formatDescription(issueNumber, issue.GetBody()),
+        KeepTitle:   true,
+    }, options)
+}
+
+// GetIssueNumberFromBranchName returns the issue number referenced by branches like feature/123-add-export, or 0 if there is none
+func GetIssueNumberFromBranchName(branchName string) int {
+    matches := regexIssueNumber.FindStringSubmatch(branchName)
+    if matches == nil {
+        return 0
+    }
+
+    issueNumber, err := strconv.Atoi(matches[1])
+    if err != nil {
+        return 0
+    }
+
+    return issueNumber
+}
+
+func formatDescription(issueNumber int, body string) string {
+    closes := fmt.Sprintf("Closes #%d", issueNumber)
+
+    body = strings.TrimSpace(body)
+    if body == "" {
+        return closes
+    }
+
+    return body + "\n\n" + closes
+}
+
+func syncOptions() drivers.SyncOptions {
+    return drivers.NewSyncOptions("GitHub", "GITHUB_ISSUES", "github-issues-sync-complete")
+}
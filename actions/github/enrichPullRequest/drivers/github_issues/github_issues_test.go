@@ -0,0 +1,135 @@
+package githubissues
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "testing"
+
+    gogithub "github.com/google/go-github/v70/github"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+// fakeGitHub serves one issue and records what Format writes to the pull request
+type fakeGitHub struct {
+    github.GitHub
+    branchName  string
+    issue       *gogithub.Issue
+    issueErr    error
+    titleData   github.TitleData
+    title       string
+    description string
+}
+
+func (gh *fakeGitHub) GetBranchName(ctx context.Context) (string, error) {
+    return gh.branchName, nil
+}
+
+func (gh *fakeGitHub) GetIssue(ctx context.Context, issueNumber int) (*gogithub.Issue, error) {
+    return gh.issue, gh.issueErr
+}
+
+func (gh *fakeGitHub) ApplyFormatting(ctx context.Context, title github.TitleData) string {
+    gh.titleData = title
+    return "[" + title.IssueKey + "] " + title.IssueName
+}
+
+func (gh *fakeGitHub) TitleSyncAllowed(ctx context.Context) (bool, error) {
+    return true, nil
+}
+
+func (gh *fakeGitHub) UpdatePR(ctx context.Context, newPRTitle string, newPRDescription string) error {
+    gh.title, gh.description = newPRTitle, newPRDescription
+    return nil
+}
+
+func (gh *fakeGitHub) UpdatePRTitle(ctx context.Context, newPRTitle string) error {
+    gh.title = newPRTitle
+    return nil
+}
+
+func TestFormat(t *testing.T) {
+    tests := []struct {
+        name                string
+        syncDescription     string
+        gh                  *fakeGitHub
+        expectedTitle       string
+        expectedDescription string
+        wantNotFound        bool
+    }{
+        {
+            name:                "issue title kept as written",
+            syncDescription:     "true",
+            gh:                  &fakeGitHub{branchName: "feature/42-oauth", issue: &gogithub.Issue{Title: gogithub.Ptr("Fix iOS OAuth bug"), Body: gogithub.Ptr("Tokens expire early.")}},
+            expectedTitle:       "[#42] Fix iOS OAuth bug",
+            expectedDescription: "Tokens expire early.\n\nCloses #42",
+        },
+        {
+            name:            "description sync disabled",
+            syncDescription: "false",
+            gh:              &fakeGitHub{branchName: "feature/42-oauth", issue: &gogithub.Issue{Title: gogithub.Ptr("Fix iOS OAuth bug"), Body: gogithub.Ptr("Tokens expire early.")}},
+            expectedTitle:   "[#42] Fix iOS OAuth bug",
+        },
+        {
+            name:         "missing issue",
+            gh:           &fakeGitHub{branchName: "feature/42-oauth", issueErr: &gogithub.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}},
+            wantNotFound: true,
+        },
+        {
+            name:         "pull request number",
+            gh:           &fakeGitHub{branchName: "feature/42-oauth", issue: &gogithub.Issue{PullRequestLinks: &gogithub.PullRequestLinks{}}},
+            wantNotFound: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("OPT_ENABLE_GITHUB_ISSUES_SYNC_DESCRIPTION", tt.syncDescription)
+            t.Setenv("OPT_ENABLE_GITHUB_ISSUES_SYNC_LABEL", "false")
+
+            err := Format(context.Background(), tt.gh)
+            if errors.Is(err, drivers.ErrNotFound) != tt.wantNotFound {
+                t.Fatalf("Format() error = %v, wantNotFound %v", err, tt.wantNotFound)
+            }
+
+            if tt.wantNotFound {
+                return
+            }
+
+            if err != nil {
+                t.Fatalf("Format() error = %v", err)
+            }
+
+            if !tt.gh.titleData.KeepIssueName {
+                t.Error("Format() asked for the issue title to be formatted like a branch name")
+            }
+
+            if tt.gh.title != tt.expectedTitle || tt.gh.description != tt.expectedDescription {
+                t.Errorf("Format() wrote title %q and description %q, want %q and %q", tt.gh.title, tt.gh.description, tt.expectedTitle, tt.expectedDescription)
+            }
+        })
+    }
+}
+
+func TestGetIssueNumberFromBranchName(t *testing.T) {
+    tests := []struct {
+        branchName string
+        expected   int
+    }{
+        {branchName: "feature/123-add-export", expected: 123},
+        {branchName: "bugfix/#45-fix-login", expected: 45},
+        {branchName: "678-update-readme", expected: 678},
+        {branchName: "feature/PROJ-123-add-export", expected: 0},
+        {branchName: "main", expected: 0},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            if actual := GetIssueNumberFromBranchName(tt.branchName); actual != tt.expected {
+                t.Errorf("GetIssueNumberFromBranchName() = %d, want %d", actual, tt.expected)
+            }
+        })
+    }
+}
@@ -0,0 +1,410 @@
+package jira
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// panelAlerts maps Jira panel types to GitHub alert types
+var panelAlerts = map[string]string{
+    "info":    "NOTE",
+    "note":    "NOTE",
+    "success": "TIP",
+    "warning": "WARNING",
+    "error":   "CAUTION",
+}
+
+var markdownEscaper = strings.NewReplacer(
+    `\`, `\\`,
+    "`", "\\`",
+    "*", `\*`,
+    "[", `\[`,
+    "]", `\]`,
+    "<", `\<`,
+)
+
+// ADFToMarkdown renders an Atlassian Document Format document as GitHub Markdown
+func ADFToMarkdown(document *models.CommentNodeScheme) string {
+    if document == nil {
+        return ""
+    }
+
+    return strings.TrimSpace(renderBlocks(document.Content))
+}
+
+func renderBlocks(nodes []*models.CommentNodeScheme) string {
+    var blocks []string
+    for _, node := range nodes {
+        if block := renderBlock(node); block != "" {
+            blocks = append(blocks, block)
+        }
+    }
+
+    return strings.Join(blocks, "\n\n")
+}
+
+func renderBlock(node *models.CommentNodeScheme) string {
+    if node == nil {
+        return ""
+    }
+
+    switch node.Type {
+    case "paragraph":
+        return renderInline(node.Content)
+    case "heading":
+        level := intAttr(node, "level", 1)
+        if level < 1 || level > 6 {
+            level = 1
+        }
+
+        return strings.Repeat("#", level) + " " + renderInline(node.Content)
+    case "bulletList", "decisionList":
+        return renderList(node, false)
+    case "orderedList":
+        return renderList(node, true)
+    case "taskList":
+        return renderTaskList(node)
+    case "codeBlock":
+        return "```" + stringAttr(node, "language") + "\n" + plainText(node.Content) + "\n```"
+    case "blockquote":
+        return prefixLines(renderBlocks(node.Content), "> ")
+    case "rule":
+        return "---"
+    case "panel":
+        return renderPanel(node)
+    case "table":
+        return renderTable(node)
+    case "mediaSingle", "mediaGroup":
+        var media []string
+        for _, child := range node.Content {
+            media = append(media, renderMedia(child))
+        }
+
+        return strings.Join(media, "\n")
+    case "media":
+        return renderMedia(node)
+    case "expand", "nestedExpand":
+        return "<details>\n<summary>" + stringAttr(node, "title") + "</summary>\n\n" + renderBlocks(node.Content) + "\n\n</details>"
+    case "blockCard", "embedCard":
+        return "<" + stringAttr(node, "url") + ">"
+    case "text", "hardBreak", "mention", "emoji", "inlineCard", "date", "status", "mediaInline":
+        return renderInline([]*models.CommentNodeScheme{node})
+    default:
+        // Unknown nodes keep their content so nothing is silently dropped
+        return renderBlocks(node.Content)
+    }
+}
+
+func renderList(node *models.CommentNodeScheme, ordered bool) string {
+    number := intAttr(node, "order", 1)
+
+    var items []string
+    for _, item := range node.Content {
+        marker := "- "
+        if ordered {
+            marker = fmt.Sprintf("%d. ", number)
+            number++
+        }
+
+        items = append(items, marker+indent(renderListItem(item), len(marker)))
+    }
+
+    return strings.Join(items, "\n")
+}
+
+func renderTaskList(node *models.CommentNodeScheme) string {
+    var items []string
+    for _, item := range node.Content {
+        marker := "- [ ] "
+        if stringAttr(item, "state") == "DONE" {
+            marker = "- [x] "
+        }
+
+        items = append(items, marker+indent(renderListItem(item), 2))
+    }
+
+    return strings.Join(items, "\n")
+}
+
+// renderListItem keeps nested lists tight against the text that introduces them
+func renderListItem(item *models.CommentNodeScheme) string {
+    var result strings.Builder
+    previousInline := true
+    for i, child := range item.Content {
+        if isInline(child.Type) {
+            result.WriteString(renderInline([]*models.CommentNodeScheme{child}))
+            continue
+        }
+
+        block := renderBlock(child)
+        if block == "" {
+            continue
+        }
+
+        if i > 0 && result.Len() > 0 {
+            if strings.HasSuffix(child.Type, "List") || previousInline {
+                result.WriteString("\n")
+            } else {
+                result.WriteString("\n\n")
+            }
+        }
+
+        result.WriteString(block)
+        previousInline = false
+    }
+
+    return result.String()
+}
+
+func renderPanel(node *models.CommentNodeScheme) string {
+    alert, ok := panelAlerts[stringAttr(node, "panelType")]
+    if !ok {
+        alert = "NOTE"
+    }
+
+    return prefixLines("[!"+alert+"]\n"+renderBlocks(node.Content), "> ")
+}
+
+func renderTable(node *models.CommentNodeScheme) string {
+    var rows [][]string
+    columns := 0
+    for _, row := range node.Content {
+        var cells []string
+        for _, cell := range row.Content {
+            cells = append(cells, renderCell(cell))
+        }
+
+        if len(cells) > columns {
+            columns = len(cells)
+        }
+
+        rows = append(rows, cells)
+    }
+
+    if columns == 0 {
+        return ""
+    }
+
+    // GitHub tables require a header row, so the first row is used whether or not Jira marked it as one
+    lines := []string{tableRow(rows[0], columns), "|" + strings.Repeat(" --- |", columns)}
+    for _, row := range rows[1:] {
+        lines = append(lines, tableRow(row, columns))
+    }
+
+    return strings.Join(lines, "\n")
+}
+
+func renderCell(cell *models.CommentNodeScheme) string {
+    text := renderBlocks(cell.Content)
+    text = strings.ReplaceAll(text, "\n\n", "<br>")
+    text = strings.ReplaceAll(text, "\n", "<br>")
+    return strings.ReplaceAll(text, "|", `\|`)
+}
+
+func tableRow(cells []string, columns int) string {
+    for len(cells) < columns {
+        cells = append(cells, "")
+    }
+
+    return "| " + strings.Join(cells, " | ") + " |"
+}
+
+func renderMedia(node *models.CommentNodeScheme) string {
+    name := markdownEscaper.Replace(stringAttr(node, "alt"))
+
+    if url := stringAttr(node, "url"); url != "" {
+        return "![" + name + "](" + url + ")"
+    }
+
+    // Jira attachments need a Jira session to view, so only a placeholder is rendered
+    if name == "" {
+        return "*\\[Attachment\\]*"
+    }
+
+    return "*\\[Attachment: " + name + "\\]*"
+}
+
+func renderInline(nodes []*models.CommentNodeScheme) string {
+    var result strings.Builder
+    for _, node := range nodes {
+        if node == nil {
+            continue
+        }
+
+        switch node.Type {
+        case "text":
+            result.WriteString(applyMarks(node.Text, node.Marks))
+        case "hardBreak":
+            result.WriteString("\n")
+        case "mention":
+            text := stringAttr(node, "text")
+            if text == "" {
+                text = stringAttr(node, "id")
+            }
+
+            if !strings.HasPrefix(text, "@") {
+                text = "@" + text
+            }
+
+            // A code span keeps GitHub from notifying an unrelated GitHub user with the same name
+            result.WriteString(codeSpan(text))
+        case "emoji":
+            if text := stringAttr(node, "text"); text != "" {
+                result.WriteString(text)
+            } else {
+                result.WriteString(stringAttr(node, "shortName"))
+            }
+        case "inlineCard":
+            result.WriteString("<" + stringAttr(node, "url") + ">")
+        case "date":
+            result.WriteString(formatDate(stringAttr(node, "timestamp")))
+        case "status":
+            result.WriteString(codeSpan(stringAttr(node, "text")))
+        case "mediaInline":
+            result.WriteString(renderMedia(node))
+        default:
+            result.WriteString(markdownEscaper.Replace(node.Text))
+            result.WriteString(renderInline(node.Content))
+        }
+    }
+
+    return result.String()
+}
+
+func applyMarks(text string, marks []*models.MarkScheme) string {
+    for _, mark := range marks {
+        if mark.Type == "code" {
+            return wrapLink(codeSpan(text), marks)
+        }
+    }
+
+    // Emphasis markers must touch the text they wrap, so surrounding whitespace stays outside them
+    core := strings.TrimSpace(text)
+    if core == "" {
+        return text
+    }
+
+    leading := text[:strings.Index(text, core)]
+    trailing := text[len(leading)+len(core):]
+
+    core = markdownEscaper.Replace(core)
+    for _, mark := range marks {
+        switch mark.Type {
+        case "strong":
+            core = "**" + core + "**"
+        case "em":
+            core = "*" + core + "*"
+        case "strike":
+            core = "~~" + core + "~~"
+        case "underline":
+            core = "<ins>" + core + "</ins>"
+        case "subsup":
+            if tag, _ := mark.Attrs["type"].(string); tag == "sub" || tag == "sup" {
+                core = "<" + tag + ">" + core + "</" + tag + ">"
+            }
+        }
+    }
+
+    return leading + wrapLink(core, marks) + trailing
+}
+
+func wrapLink(text string, marks []*models.MarkScheme) string {
+    for _, mark := range marks {
+        if href, _ := mark.Attrs["href"].(string); mark.Type == "link" && href != "" {
+            return "[" + text + "](" + href + ")"
+        }
+    }
+
+    return text
+}
+
+func codeSpan(text string) string {
+    if strings.Contains(text, "`") {
+        return "`` " + text + " ``"
+    }
+
+    return "`" + text + "`"
+}
+
+func formatDate(timestamp string) string {
+    milliseconds, err := strconv.ParseInt(timestamp, 10, 64)
+    if err != nil {
+        return timestamp
+    }
+
+    return time.UnixMilli(milliseconds).UTC().Format("2006-01-02")
+}
+
+func plainText(nodes []*models.CommentNodeScheme) string {
+    var result strings.Builder
+    for _, node := range nodes {
+        if node.Type == "hardBreak" {
+            result.WriteString("\n")
+        }
+
+        result.WriteString(node.Text)
+        result.WriteString(plainText(node.Content))
+    }
+
+    return result.String()
+}
+
+func isInline(nodeType string) bool {
+    switch nodeType {
+    case "text", "hardBreak", "mention", "emoji", "inlineCard", "date", "status", "mediaInline":
+        return true
+    }
+
+    return false
+}
+
+func indent(text string, width int) string {
+    padding := strings.Repeat(" ", width)
+    lines := strings.Split(text, "\n")
+    for i := 1; i < len(lines); i++ {
+        if lines[i] != "" {
+            lines[i] = padding + lines[i]
+        }
+    }
+
+    return strings.Join(lines, "\n")
+}
+
+func prefixLines(text string, prefix string) string {
+    lines := strings.Split(text, "\n")
+    for i, line := range lines {
+        if line == "" {
+            lines[i] = strings.TrimSpace(prefix)
+        } else {
+            lines[i] = prefix + line
+        }
+    }
+
+    return strings.Join(lines, "\n")
+}
+
+func stringAttr(node *models.CommentNodeScheme, name string) string {
+    switch value := node.Attrs[name].(type) {
+    case string:
+        return value
+    case float64:
+        return strconv.FormatFloat(value, 'f', -1, 64)
+    }
+
+    return ""
+}
+
+func intAttr(node *models.CommentNodeScheme, name string, fallback int) int {
+    switch value := node.Attrs[name].(type) {
+    case float64:
+        return int(value)
+    case int:
+        return value
+    }
+
+    return fallback
+}
@@ -0,0 +1,53 @@
+package jira
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestADFToMarkdown(t *testing.T) {
+    documents, err := filepath.Glob(filepath.Join("test_files", "adf", "*.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if len(documents) == 0 {
+        t.Fatal("no ADF test documents found")
+    }
+
+    for _, documentPath := range documents {
+        name := strings.TrimSuffix(filepath.Base(documentPath), ".json")
+        t.Run(name, func(t *testing.T) {
+            source, err := os.ReadFile(documentPath)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            var document models.CommentNodeScheme
+            if err := json.Unmarshal(source, &document); err != nil {
+                t.Fatalf("failed to parse %s: %v", documentPath, err)
+            }
+
+            expected, err := os.ReadFile(strings.TrimSuffix(documentPath, ".json") + ".md")
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            actual := ADFToMarkdown(&document)
+            if actual != strings.TrimSuffix(string(expected), "\n") {
+                t.Errorf("ADFToMarkdown() mismatch for %s\n--- got ---\n%s\n--- want ---\n%s", documentPath, actual, expected)
+            }
+        })
+    }
+}
+
+func TestADFToMarkdownNil(t *testing.T) {
+    if actual := ADFToMarkdown(nil); actual != "" {
+        t.Errorf("ADFToMarkdown(nil) = %q, want empty string", actual)
+    }
+}
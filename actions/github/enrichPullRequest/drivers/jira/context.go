@@ -0,0 +1,179 @@
+package jira
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const envContext = "OPT_JIRA_CONTEXT"
+const envSprintField = "OPT_JIRA_SPRINT_FIELD"
+
+// defaultSprintField is the Sprint field on Jira Cloud; Jira Server and Data Center assign it a different ID on each instance
+const defaultSprintField = "customfield_10020"
+
+var (
+    regexLegacySprintState = regexp.MustCompile(`[\[,]state=([^,\]]*)`)
+    regexLegacySprintName  = regexp.MustCompile(`[\[,]name=([^,\]]*)`)
+)
+
+// parentFields holds the parent issue details Jira embeds in the issue response
+type parentFields struct {
+    Fields struct {
+        Summary   string `json:"summary"`
+        IssueType struct {
+            Name string `json:"name"`
+        } `json:"issuetype"`
+    } `json:"fields"`
+}
+
+// contextEnabled reports whether the epic and sprint are surfaced in the given place: title, description, or labels
+func contextEnabled(place string) bool {
+    for _, configured := range parseFieldList(os.Getenv(envContext)) {
+        if configured == place {
+            return true
+        }
+    }
+
+    return false
+}
+
+func sprintField() string {
+    if field := strings.TrimSpace(os.Getenv(envSprintField)); field != "" {
+        return field
+    }
+
+    return defaultSprintField
+}
+
+// parentDetails reads the parent's type and summary from the issue response, so the parent does not have to be fetched separately
+func parentDetails(rawFields map[string]json.RawMessage) (string, string) {
+    raw, ok := rawFields["parent"]
+    if !ok {
+        return "", ""
+    }
+
+    var parent parentFields
+    if err := json.Unmarshal(raw, &parent); err != nil {
+        return "", ""
+    }
+
+    return parent.Fields.IssueType.Name, parent.Fields.Summary
+}
+
+// activeSprint returns the name of the active sprint in the Sprint field. Jira Cloud and recent Data Center versions return sprint
+// objects, while older Server versions return strings such as "com.atlassian.greenhopper.service.sprint.Sprint@1f[id=1,state=ACTIVE,name=Sprint 14]".
+func activeSprint(raw json.RawMessage) string {
+    var sprints []interface{}
+    if err := json.Unmarshal(raw, &sprints); err != nil {
+        return ""
+    }
+
+    for _, sprint := range sprints {
+        switch typed := sprint.(type) {
+        case map[string]interface{}:
+            if state, _ := typed["state"].(string); strings.EqualFold(state, "active") {
+                name, _ := typed["name"].(string)
+                return name
+            }
+        case string:
+            state := regexLegacySprintState.FindStringSubmatch(typed)
+            name := regexLegacySprintName.FindStringSubmatch(typed)
+            if state != nil && name != nil && strings.EqualFold(state[1], "active") {
+                return name[1]
+            }
+        }
+    }
+
+    return ""
+}
+
+// epic returns the issue's epic, which is its parent when the parent is an epic
+func (jiraIssue *issue) epic() (string, string) {
+    if jiraIssue.ParentKey == "" || !strings.EqualFold(jiraIssue.ParentType, "epic") {
+        return "", ""
+    }
+
+    return jiraIssue.ParentKey, jiraIssue.ParentSummary
+}
+
+// contextHeader renders the epic and active sprint as a line for the top of the synced description
+func contextHeader(baseURL string, jiraIssue *issue) string {
+    var parts []string
+    if epicKey, epicName := jiraIssue.epic(); epicKey != "" {
+        epic := fmt.Sprintf("**Epic:** [%s](%s/browse/%s)", epicKey, strings.TrimSuffix(baseURL, "/"), epicKey)
+        if epicName != "" {
+            epic += " " + markdownEscaper.Replace(epicName)
+        }
+
+        parts = append(parts, epic)
+    }
+
+    if jiraIssue.Sprint != "" {
+        parts = append(parts, "**Sprint:** "+markdownEscaper.Replace(jiraIssue.Sprint))
+    }
+
+    return strings.Join(parts, " · ")
+}
+
+// contextLabels builds epic:* and sprint:* labels, naming the epic by its summary when Jira provides one
+func contextLabels(jiraIssue *issue) []fieldLabel {
+    var labels []fieldLabel
+    if epicKey, epicName := jiraIssue.epic(); epicKey != "" {
+        if epicName == "" {
+            epicName = epicKey
+        }
+
+        labels = append(labels, fieldLabel{Name: "epic:" + labelValue(epicName), Description: "Jira epic " + epicKey, Color: "5319e7"})
+    }
+
+    if jiraIssue.Sprint != "" {
+        labels = append(labels, fieldLabel{Name: "sprint:" + labelValue(jiraIssue.Sprint), Description: "Jira sprint", Color: "0e8a16"})
+    }
+
+    return labels
+}
+
+// syncContextLabels removes epic:* and sprint:* labels left from an earlier epic or sprint before adding the current ones
+func syncContextLabels(ctx context.Context, gh github.GitHub, jiraIssue *issue) error {
+    if jiraIssue == nil || !contextEnabled("labels") {
+        return nil
+    }
+
+    labels := contextLabels(jiraIssue)
+    current := make(map[string]bool)
+    for _, label := range labels {
+        current[label.Name] = true
+    }
+
+    pullRequest, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return err
+    }
+
+    var errs []error
+    for _, label := range pullRequest.Labels {
+        name := label.GetName()
+        if current[name] || !(strings.HasPrefix(name, "epic:") || strings.HasPrefix(name, "sprint:")) {
+            continue
+        }
+
+        // A label someone else already removed needs no further work
+        if err := gh.RemoveLabelFromPR(ctx, name); err != nil && !github.IsNotFound(err) {
+            errs = append(errs, err)
+        }
+    }
+
+    for _, label := range labels {
+        errs = append(errs, drivers.ApplyLabel(ctx, gh, label.Name, label.Description, label.Color))
+    }
+
+    return errors.Join(errs...)
+}
@@ -0,0 +1,159 @@
+package jira
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    gogithub "github.com/google/go-github/v70/github"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+// fakeGitHub serves the branch and the pull request's labels, and records the labels and comments the driver adds
+type fakeGitHub struct {
+    github.GitHub
+    branchName string
+    labels     []string
+    added      []string
+    removed    []string
+    comments   []string
+    action     string
+    merged     bool
+}
+
+func (gh *fakeGitHub) PullRequestAction() (string, bool) {
+    return gh.action, gh.merged
+}
+
+func (gh *fakeGitHub) GetBranchName(ctx context.Context) (string, error) {
+    return gh.branchName, nil
+}
+
+func (gh *fakeGitHub) AddPRComment(ctx context.Context, comment string) error {
+    gh.comments = append(gh.comments, comment)
+    return nil
+}
+
+func (gh *fakeGitHub) GetPRInformation(ctx context.Context) (*gogithub.PullRequest, error) {
+    pullRequest := &gogithub.PullRequest{}
+    for _, label := range gh.labels {
+        pullRequest.Labels = append(pullRequest.Labels, &gogithub.Label{Name: gogithub.Ptr(label)})
+    }
+
+    return pullRequest, nil
+}
+
+func (gh *fakeGitHub) EnsureLabelExists(ctx context.Context, labelName string, description string, color string) error {
+    return nil
+}
+
+func (gh *fakeGitHub) AddLabelToPR(ctx context.Context, labelName string) error {
+    gh.added = append(gh.added, labelName)
+    return nil
+}
+
+func (gh *fakeGitHub) RemoveLabelFromPR(ctx context.Context, labelName string) error {
+    gh.removed = append(gh.removed, labelName)
+    return nil
+}
+
+func TestActiveSprint(t *testing.T) {
+    tests := []struct {
+        name     string
+        raw      string
+        expected string
+    }{
+        {name: "no sprint", raw: `null`, expected: ""},
+        {name: "active sprint object", raw: `[{"id":1,"name":"Sprint 13","state":"closed"},{"id":2,"name":"Sprint 14","state":"active"}]`, expected: "Sprint 14"},
+        {name: "only future sprint", raw: `[{"id":3,"name":"Sprint 15","state":"future"}]`, expected: ""},
+        {
+            name:     "legacy server string",
+            raw:      `["com.atlassian.greenhopper.service.sprint.Sprint@1f[id=2,rapidViewId=5,state=ACTIVE,name=Sprint 14,startDate=2026-10-05T09:00:00.000Z]"]`,
+            expected: "Sprint 14",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if actual := activeSprint(json.RawMessage(tt.raw)); actual != tt.expected {
+                t.Errorf("activeSprint() = %q, want %q", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestContextHeaderAndLabels(t *testing.T) {
+    jiraIssue := &issue{ParentKey: "PROJ-1", ParentType: "Epic", ParentSummary: "Checkout Redesign", Sprint: "Sprint 14"}
+
+    expectedHeader := "**Epic:** [PROJ-1](https://example.atlassian.net/browse/PROJ-1) Checkout Redesign · **Sprint:** Sprint 14"
+    if header := contextHeader("https://example.atlassian.net/", jiraIssue); header != expectedHeader {
+        t.Errorf("contextHeader() = %q, want %q", header, expectedHeader)
+    }
+
+    labels := contextLabels(jiraIssue)
+    if len(labels) != 2 || labels[0].Name != "epic:checkout-redesign" || labels[1].Name != "sprint:sprint-14" {
+        t.Errorf("contextLabels() = %+v", labels)
+    }
+
+    story := &issue{ParentKey: "PROJ-2", ParentType: "Story"}
+    if header := contextHeader("https://example.atlassian.net", story); header != "" {
+        t.Errorf("contextHeader() for a non-epic parent = %q, want empty", header)
+    }
+}
+
+func TestSyncContextLabelsRemovesStaleLabels(t *testing.T) {
+    t.Setenv(envContext, "labels")
+
+    gh := &fakeGitHub{labels: []string{"epic:checkout-redesign", "sprint:sprint-13", "sprint:sprint-14", "backend"}}
+    jiraIssue := &issue{ParentKey: "PROJ-1", ParentType: "Epic", ParentSummary: "Checkout Redesign", Sprint: "Sprint 14"}
+
+    if err := syncContextLabels(context.Background(), gh, jiraIssue); err != nil {
+        t.Fatalf("syncContextLabels() error = %v", err)
+    }
+
+    if !reflect.DeepEqual(gh.removed, []string{"sprint:sprint-13"}) {
+        t.Errorf("syncContextLabels() removed %q, want the previous sprint only", gh.removed)
+    }
+
+    if !reflect.DeepEqual(gh.added, []string{"epic:checkout-redesign", "sprint:sprint-14"}) {
+        t.Errorf("syncContextLabels() added %q", gh.added)
+    }
+}
+
+func TestGetParentIssuePrefixReusesParent(t *testing.T) {
+    var requests []string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        requests = append(requests, r.URL.Path)
+        w.Write([]byte(`{"key":"PROJ-12","fields":{"summary":"Add export",` +
+            `"parent":{"key":"PROJ-1","fields":{"summary":"Checkout Redesign","issuetype":{"name":"Epic"}}},` +
+            `"customfield_10020":[{"id":2,"name":"Sprint 14","state":"active"}]}}`))
+    }))
+    defer server.Close()
+
+    client, err := createJiraClient(Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", DeploymentType: deploymentCloud})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    jiraIssue, err := client.getIssue("PROJ-12")
+    if err != nil {
+        t.Fatalf("getIssue() error = %v", err)
+    }
+
+    prefix, err := getParentIssuePrefix(client, jiraIssue)
+    if err != nil || prefix != "" {
+        t.Errorf("getParentIssuePrefix() = %q, %v, want no prefix for an epic parent", prefix, err)
+    }
+
+    if epicKey, epicName := jiraIssue.epic(); epicKey != "PROJ-1" || epicName != "Checkout Redesign" || jiraIssue.Sprint != "Sprint 14" {
+        t.Errorf("epic() = %q, %q, sprint = %q", epicKey, epicName, jiraIssue.Sprint)
+    }
+
+    if len(requests) != 1 {
+        t.Errorf("expected a single Jira request, got %v", requests)
+    }
+}
@@ -0,0 +1,157 @@
+package jira
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+const envCustomFields = "OPT_JIRA_CUSTOM_FIELDS"
+
+// customField is a Jira custom field rendered as a section of the synced description
+type customField struct {
+    ID      string
+    Heading string
+}
+
+// parseCustomFields reads field ID to heading pairs such as "customfield_10034:Acceptance Criteria,customfield_10035:Test Notes"
+func parseCustomFields(value string) []customField {
+    var fields []customField
+    for _, pair := range strings.Split(value, ",") {
+        id, heading, _ := strings.Cut(pair, ":")
+        id = strings.TrimSpace(id)
+        heading = strings.TrimSpace(heading)
+        if id == "" {
+            continue
+        }
+
+        if heading == "" {
+            heading = id
+        }
+
+        fields = append(fields, customField{ID: id, Heading: heading})
+    }
+
+    return fields
+}
+
+func configuredCustomFields() []customField {
+    return parseCustomFields(os.Getenv(envCustomFields))
+}
+
+// rawIssueFields decodes the fields of the raw issue response, which include the custom fields and parent details the typed issue omits
+func rawIssueFields(body []byte) map[string]json.RawMessage {
+    var payload struct {
+        Fields map[string]json.RawMessage `json:"fields"`
+    }
+
+    if err := json.Unmarshal(body, &payload); err != nil {
+        logger.Errorf("Failed to read Jira issue fields: %v", err)
+        return nil
+    }
+
+    return payload.Fields
+}
+
+// renderCustomFields renders the configured custom fields. Text values are rendered with renderText, which converts wiki markup on Jira
+// Server; rich text on Jira Cloud arrives as ADF.
+func renderCustomFields(rawFields map[string]json.RawMessage, fields []customField, renderText func(string) string) map[string]string {
+    if len(fields) == 0 {
+        return nil
+    }
+
+    values := make(map[string]string)
+    for _, field := range fields {
+        raw, ok := rawFields[field.ID]
+        if !ok {
+            logger.Infof("Jira custom field %s is not set on this issue or is not visible to the Jira user", field.ID)
+            continue
+        }
+
+        if value := strings.TrimSpace(renderCustomFieldValue(raw, renderText)); value != "" {
+            values[field.ID] = value
+        }
+    }
+
+    return values
+}
+
+func renderCustomFieldValue(raw json.RawMessage, renderText func(string) string) string {
+    var value interface{}
+    if err := json.Unmarshal(raw, &value); err != nil {
+        return ""
+    }
+
+    switch typed := value.(type) {
+    case nil:
+        return ""
+    case string:
+        return renderText(typed)
+    case []interface{}:
+        var items []string
+        for _, item := range typed {
+            if text := customFieldText(item); text != "" {
+                items = append(items, "- "+text)
+            }
+        }
+
+        return strings.Join(items, "\n")
+    case map[string]interface{}:
+        if typed["type"] == "doc" {
+            var document models.CommentNodeScheme
+            if err := json.Unmarshal(raw, &document); err != nil {
+                return ""
+            }
+
+            return ADFToMarkdown(&document)
+        }
+
+        return customFieldText(typed)
+    default:
+        return customFieldText(typed)
+    }
+}
+
+// customFieldText renders a single value: select options, users, versions and other named objects by their display text
+func customFieldText(value interface{}) string {
+    switch typed := value.(type) {
+    case string:
+        return markdownEscaper.Replace(typed)
+    case float64, bool:
+        return fmt.Sprint(typed)
+    case map[string]interface{}:
+        for _, key := range []string{"value", "displayName", "name", "key"} {
+            if text, ok := typed[key].(string); ok && text != "" {
+                // Cascading selects carry the selected child option alongside the parent
+                if child := customFieldText(typed["child"]); key == "value" && child != "" {
+                    return markdownEscaper.Replace(text) + " › " + child
+                }
+
+                return markdownEscaper.Replace(text)
+            }
+        }
+    }
+
+    return ""
+}
+
+// customFieldValues renders the issue's values for the custom fields
+func (jiraIssue *issue) customFieldValues(fields []customField) map[string]string {
+    return renderCustomFields(jiraIssue.rawFields, fields, jiraIssue.renderText)
+}
+
+// withCustomFieldSections appends each configured custom field that has a value as a section after the description
+func withCustomFieldSections(description string, fields []customField, values map[string]string) string {
+    sections := []string{description}
+    for _, field := range fields {
+        if value, ok := values[field.ID]; ok {
+            sections = append(sections, "### "+field.Heading+"\n\n"+value)
+        }
+    }
+
+    return strings.TrimSpace(strings.Join(sections, "\n\n"))
+}
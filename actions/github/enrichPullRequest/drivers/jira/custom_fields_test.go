@@ -0,0 +1,101 @@
+package jira
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestParseCustomFields(t *testing.T) {
+    fields := parseCustomFields("customfield_10034:Acceptance Criteria, customfield_10035 : Test Notes,,customfield_10036")
+
+    expected := []customField{
+        {ID: "customfield_10034", Heading: "Acceptance Criteria"},
+        {ID: "customfield_10035", Heading: "Test Notes"},
+        {ID: "customfield_10036", Heading: "customfield_10036"},
+    }
+
+    if len(fields) != len(expected) {
+        t.Fatalf("parseCustomFields() = %+v, want %+v", fields, expected)
+    }
+
+    for i := range expected {
+        if fields[i] != expected[i] {
+            t.Errorf("parseCustomFields()[%d] = %+v, want %+v", i, fields[i], expected[i])
+        }
+    }
+}
+
+func TestRenderCustomFields(t *testing.T) {
+    body := []byte(`{"key":"PROJ-12","fields":{` +
+        `"customfield_10034":{"type":"doc","version":1,"content":[{"type":"bulletList","content":[` +
+        `{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"Exports CSV"}]}]}]}]},` +
+        `"customfield_10035":"Run the *export* suite",` +
+        `"customfield_10036":{"value":"Web","child":{"value":"Checkout"}},` +
+        `"customfield_10037":[{"value":"iOS"},{"value":"Android"}],` +
+        `"customfield_10038":3,` +
+        `"customfield_10039":null}}`)
+
+    fields := parseCustomFields("customfield_10034:Acceptance Criteria,customfield_10035:Test Notes,customfield_10036:Area," +
+        "customfield_10037:Platforms,customfield_10038:Story Points,customfield_10039:Release Notes,customfield_10040:Missing")
+
+    values := renderCustomFields(rawIssueFields(body), fields, WikiToMarkdown)
+
+    expected := map[string]string{
+        "customfield_10034": "- Exports CSV",
+        "customfield_10035": "Run the **export** suite",
+        "customfield_10036": "Web › Checkout",
+        "customfield_10037": "- iOS\n- Android",
+        "customfield_10038": "3",
+    }
+
+    if len(values) != len(expected) {
+        t.Errorf("renderCustomFields() = %q, want %q", values, expected)
+    }
+
+    for id, value := range expected {
+        if values[id] != value {
+            t.Errorf("renderCustomFields()[%s] = %q, want %q", id, values[id], value)
+        }
+    }
+}
+
+func TestWithCustomFieldSections(t *testing.T) {
+    fields := parseCustomFields("customfield_10034:Acceptance Criteria,customfield_10035:Test Notes")
+    values := map[string]string{"customfield_10035": "Run the export suite"}
+
+    expected := "Adds CSV export.\n\n### Test Notes\n\nRun the export suite"
+    if actual := withCustomFieldSections("Adds CSV export.", fields, values); actual != expected {
+        t.Errorf("withCustomFieldSections() = %q, want %q", actual, expected)
+    }
+
+    if actual := withCustomFieldSections("", fields, values); actual != "### Test Notes\n\nRun the export suite" {
+        t.Errorf("withCustomFieldSections() with no description = %q", actual)
+    }
+}
+
+func TestGetJiraInfoCustomFields(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(`{"key":"PROJ-12","fields":{"summary":"Add export","description":"h1. Export","customfield_10034":"* Exports CSV"}}`))
+    }))
+    defer server.Close()
+
+    config := Configuration{
+        Enable:         true,
+        URL:            server.URL,
+        Token:          "token",
+        IssueKey:       "PROJ-12",
+        DeploymentType: deploymentServer,
+        CustomFields:   parseCustomFields("customfield_10034:Acceptance Criteria"),
+    }
+
+    jira, err := getJiraInfo(config)
+    if err != nil {
+        t.Fatalf("getJiraInfo() error = %v", err)
+    }
+
+    expected := "# Export\n\n### Acceptance Criteria\n\n- Exports CSV"
+    if jira.Description != expected {
+        t.Errorf("getJiraInfo() description = %q, want %q", jira.Description, expected)
+    }
+}
@@ -0,0 +1,294 @@
+package jira
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const envFieldLabels = "OPT_JIRA_FIELD_LABELS"
+const envUserMap = "OPT_JIRA_USER_MAP"
+const envReviewers = "OPT_JIRA_REVIEWERS"
+const envAssignees = "OPT_JIRA_ASSIGNEES"
+const envMilestone = "OPT_JIRA_MILESTONE"
+
+// fieldLabelColors holds the color of the labels created for each Jira field that can be mapped to labels
+var fieldLabelColors = map[string]string{
+    "type":      "1d76db",
+    "priority":  "fbca04",
+    "component": "c5def5",
+}
+
+// jiraUser holds the identifiers a user map entry may use for a Jira user
+type jiraUser struct {
+    AccountID   string
+    Email       string
+    DisplayName string
+    Name        string
+}
+
+type fieldLabel struct {
+    Name        string
+    Description string
+    Color       string
+}
+
+func newJiraUser(user *models.UserScheme) *jiraUser {
+    if user == nil {
+        return nil
+    }
+
+    return &jiraUser{
+        AccountID:   user.AccountID,
+        Email:       user.EmailAddress,
+        DisplayName: user.DisplayName,
+        Name:        user.Name,
+    }
+}
+
+func componentNames(components []*models.ComponentScheme) []string {
+    var names []string
+    for _, component := range components {
+        names = append(names, component.Name)
+    }
+
+    return names
+}
+
+func versionNames(versions []*models.VersionScheme) []string {
+    var names []string
+    for _, version := range versions {
+        names = append(names, version.Name)
+    }
+
+    return names
+}
+
+// parseFieldList reads a comma-separated list such as "type,priority" into lower-case names
+func parseFieldList(value string) []string {
+    var fields []string
+    for _, field := range strings.Split(value, ",") {
+        if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+            fields = append(fields, field)
+        }
+    }
+
+    return fields
+}
+
+// validateFieldMappings rejects unknown field names and an unusable user map, so a typo in the workflow fails the run before any API work
+func validateFieldMappings() error {
+    for _, field := range parseFieldList(os.Getenv(envFieldLabels)) {
+        if _, ok := fieldLabelColors[field]; !ok {
+            return fmt.Errorf("invalid %s: unknown field %q; expected type, priority, or component", envFieldLabels, field)
+        }
+    }
+
+    var mapsUsers bool
+    for _, envVar := range []string{envReviewers, envAssignees} {
+        for _, field := range parseFieldList(os.Getenv(envVar)) {
+            if field != "assignee" && field != "reporter" {
+                return fmt.Errorf("invalid %s: unknown field %q; expected assignee or reporter", envVar, field)
+            }
+
+            mapsUsers = true
+        }
+    }
+
+    if mapsUsers {
+        if _, err := loadUserMap(os.Getenv(envUserMap)); err != nil {
+            return fmt.Errorf("unable to map Jira users to GitHub users: %v", err)
+        }
+    }
+
+    return nil
+}
+
+// syncFieldMappings applies the configured Jira field mappings to the pull request. Every mapping is off unless configured, and a
+// mapping GitHub rejects does not stop the others.
+func syncFieldMappings(ctx context.Context, gh github.GitHub, jiraIssue *issue) error {
+    if jiraIssue == nil {
+        return nil
+    }
+
+    labels, err := fieldLabels(jiraIssue, parseFieldList(os.Getenv(envFieldLabels)))
+    if err != nil {
+        err = fmt.Errorf("invalid %s: %v", envFieldLabels, err)
+    }
+
+    errs := []error{err}
+    for _, label := range labels {
+        errs = append(errs, drivers.ApplyLabel(ctx, gh, label.Name, label.Description, label.Color))
+    }
+
+    errs = append(errs, syncUsers(ctx, gh, jiraIssue))
+
+    if strings.ToLower(os.Getenv(envMilestone)) == "true" && len(jiraIssue.FixVersions) > 0 {
+        if len(jiraIssue.FixVersions) > 1 {
+            logger.Infof("Jira issue has %d fix versions; using '%s' for the milestone", len(jiraIssue.FixVersions), jiraIssue.FixVersions[0])
+        }
+
+        errs = append(errs, gh.SetMilestone(ctx, jiraIssue.FixVersions[0]))
+    }
+
+    return errors.Join(errs...)
+}
+
+// fieldLabels builds labels such as type:bug, priority:high, and component:billing for the requested fields
+func fieldLabels(jiraIssue *issue, fields []string) ([]fieldLabel, error) {
+    var labels []fieldLabel
+    for _, field := range fields {
+        var values []string
+        switch field {
+        case "type":
+            values = []string{jiraIssue.IssueType}
+        case "priority":
+            values = []string{jiraIssue.Priority}
+        case "component":
+            values = jiraIssue.Components
+        default:
+            return labels, fmt.Errorf("unknown field %q; expected type, priority, or component", field)
+        }
+
+        for _, value := range values {
+            if value = strings.TrimSpace(value); value == "" {
+                continue
+            }
+
+            labels = append(labels, fieldLabel{
+                Name:        field + ":" + labelValue(value),
+                Description: "Synced from the Jira " + field + " field",
+                Color:       fieldLabelColors[field],
+            })
+        }
+    }
+
+    return labels, nil
+}
+
+// labelValue lower-cases a Jira value and replaces its spaces with hyphens for use in a label such as component:customer-portal
+func labelValue(value string) string {
+    return strings.ToLower(strings.Join(strings.Fields(value), "-"))
+}
+
+func syncUsers(ctx context.Context, gh github.GitHub, jiraIssue *issue) error {
+    reviewerFields := parseFieldList(os.Getenv(envReviewers))
+    assigneeFields := parseFieldList(os.Getenv(envAssignees))
+    if len(reviewerFields) == 0 && len(assigneeFields) == 0 {
+        return nil
+    }
+
+    userMap, err := loadUserMap(os.Getenv(envUserMap))
+    if err != nil {
+        return fmt.Errorf("unable to map Jira users to GitHub users: %v", err)
+    }
+
+    pullRequest, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return err
+    }
+
+    // GitHub does not allow a PR author to be requested as a reviewer of their own PR
+    author := pullRequest.GetUser().GetLogin()
+
+    var reviewers []string
+    for _, login := range githubLogins(jiraIssue, reviewerFields, userMap) {
+        if !strings.EqualFold(login, author) {
+            reviewers = append(reviewers, login)
+        }
+    }
+
+    var errs []error
+    if len(reviewers) > 0 {
+        errs = append(errs, gh.RequestReviewers(ctx, reviewers))
+    }
+
+    if assignees := githubLogins(jiraIssue, assigneeFields, userMap); len(assignees) > 0 {
+        errs = append(errs, gh.AddAssignees(ctx, assignees))
+    }
+
+    return errors.Join(errs...)
+}
+
+// loadUserMap reads a JSON object mapping Jira users, by account ID, email address, display name, or Server username, to GitHub logins
+func loadUserMap(path string) (map[string]string, error) {
+    if path == "" {
+        return nil, fmt.Errorf("%s is not set", envUserMap)
+    }
+
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read user map: %v", err)
+    }
+
+    var entries map[string]string
+    if err := json.Unmarshal(content, &entries); err != nil {
+        return nil, fmt.Errorf("failed to parse user map %s: %v", path, err)
+    }
+
+    userMap := make(map[string]string, len(entries))
+    for jiraIdentifier, login := range entries {
+        userMap[strings.ToLower(strings.TrimSpace(jiraIdentifier))] = strings.TrimPrefix(strings.TrimSpace(login), "@")
+    }
+
+    return userMap, nil
+}
+
+// githubLogins maps the Jira users in the given fields (assignee or reporter) to GitHub logins, skipping users with no mapping
+func githubLogins(jiraIssue *issue, fields []string, userMap map[string]string) []string {
+    seen := make(map[string]bool)
+
+    var logins []string
+    for _, field := range fields {
+        var user *jiraUser
+        switch field {
+        case "assignee":
+            user = jiraIssue.Assignee
+        case "reporter":
+            user = jiraIssue.Reporter
+        default:
+            logger.Errorf("Unknown Jira user field %q; expected assignee or reporter", field)
+            continue
+        }
+
+        if user == nil {
+            continue
+        }
+
+        login, ok := user.githubLogin(userMap)
+        if !ok {
+            logger.Infof("No GitHub user mapped for Jira %s %s", field, user.DisplayName)
+            continue
+        }
+
+        if !seen[strings.ToLower(login)] {
+            seen[strings.ToLower(login)] = true
+            logins = append(logins, login)
+        }
+    }
+
+    return logins
+}
+
+func (user *jiraUser) githubLogin(userMap map[string]string) (string, bool) {
+    for _, identifier := range []string{user.AccountID, user.Email, user.Name, user.DisplayName} {
+        if identifier == "" {
+            continue
+        }
+
+        if login, ok := userMap[strings.ToLower(identifier)]; ok && login != "" {
+            return login, true
+        }
+    }
+
+    return "", false
+}
@@ -0,0 +1,162 @@
+package jira
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestFieldLabels(t *testing.T) {
+    jiraIssue := &issue{IssueType: "Sub-task", Priority: "High", Components: []string{"Billing", "Customer Portal"}}
+
+    tests := []struct {
+        name     string
+        fields   string
+        expected []string
+        wantErr  bool
+    }{
+        {name: "no fields", fields: "", expected: nil},
+        {name: "type and priority", fields: "type, priority", expected: []string{"type:sub-task", "priority:high"}},
+        {name: "components", fields: "component", expected: []string{"component:billing", "component:customer-portal"}},
+        {name: "unknown field", fields: "type,resolution", expected: []string{"type:sub-task"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            labels, err := fieldLabels(jiraIssue, parseFieldList(tt.fields))
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("fieldLabels() error = %v, wantErr %v", err, tt.wantErr)
+            }
+
+            var names []string
+            for _, label := range labels {
+                names = append(names, label.Name)
+            }
+
+            if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+                t.Errorf("fieldLabels() = %v, want %v", names, tt.expected)
+            }
+        })
+    }
+}
+
+func TestGitHubLogins(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "jira-users.json")
+    content := `{"5b10ac8d82e05b22cc7d4ef5": "@octocat", "Jane.Doe@example.com": "janedoe", "jsmith": "jsmith-gh"}`
+    if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+        t.Fatal(err)
+    }
+
+    userMap, err := loadUserMap(path)
+    if err != nil {
+        t.Fatalf("loadUserMap() error = %v", err)
+    }
+
+    jiraIssue := &issue{
+        Assignee: &jiraUser{AccountID: "5b10ac8d82e05b22cc7d4ef5", DisplayName: "Octo Cat"},
+        Reporter: &jiraUser{Email: "jane.doe@example.com", DisplayName: "Jane Doe"},
+    }
+
+    tests := []struct {
+        name     string
+        fields   []string
+        issue    *issue
+        expected []string
+    }{
+        {name: "assignee by account ID", fields: []string{"assignee"}, issue: jiraIssue, expected: []string{"octocat"}},
+        {name: "reporter by email", fields: []string{"reporter", "assignee"}, issue: jiraIssue, expected: []string{"janedoe", "octocat"}},
+        {name: "server username", fields: []string{"assignee"}, issue: &issue{Assignee: &jiraUser{Name: "jsmith"}}, expected: []string{"jsmith-gh"}},
+        {name: "unmapped user", fields: []string{"assignee"}, issue: &issue{Assignee: &jiraUser{DisplayName: "Someone Else"}}, expected: nil},
+        {name: "unassigned", fields: []string{"assignee"}, issue: &issue{}, expected: nil},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            logins := githubLogins(tt.issue, tt.fields, userMap)
+            if strings.Join(logins, ",") != strings.Join(tt.expected, ",") {
+                t.Errorf("githubLogins() = %v, want %v", logins, tt.expected)
+            }
+        })
+    }
+}
+
+func TestLoadUserMapErrors(t *testing.T) {
+    if _, err := loadUserMap(""); err == nil {
+        t.Error("loadUserMap() should fail when no path is configured")
+    }
+
+    path := filepath.Join(t.TempDir(), "jira-users.json")
+    if err := os.WriteFile(path, []byte(`["octocat"]`), 0o644); err != nil {
+        t.Fatal(err)
+    }
+
+    if _, err := loadUserMap(path); err == nil {
+        t.Error("loadUserMap() should fail when the file is not a JSON object")
+    }
+}
+
+func TestGetIssueMappedFields(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(`{"key":"PROJ-12","fields":{"summary":"Add export","priority":{"name":"High"},` +
+            `"components":[{"name":"Billing"}],"fixVersions":[{"name":"2.4.0"}],` +
+            `"assignee":{"accountId":"abc123","displayName":"Octo Cat"},"reporter":{"accountId":"def456","displayName":"Jane Doe"}}}`))
+    }))
+    defer server.Close()
+
+    client, err := createJiraClient(Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", DeploymentType: deploymentCloud})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    jiraIssue, err := client.getIssue("PROJ-12")
+    if err != nil {
+        t.Fatalf("getIssue() error = %v", err)
+    }
+
+    if jiraIssue.Priority != "High" || strings.Join(jiraIssue.Components, ",") != "Billing" || strings.Join(jiraIssue.FixVersions, ",") != "2.4.0" {
+        t.Errorf("getIssue() = %+v, want priority, components, and fix versions", jiraIssue)
+    }
+
+    if jiraIssue.Assignee == nil || jiraIssue.Assignee.AccountID != "abc123" || jiraIssue.Reporter == nil || jiraIssue.Reporter.AccountID != "def456" {
+        t.Errorf("getIssue() assignee = %+v, reporter = %+v", jiraIssue.Assignee, jiraIssue.Reporter)
+    }
+}
+
+func TestValidateFieldMappings(t *testing.T) {
+    userMap := filepath.Join(t.TempDir(), "jira-users.json")
+    if err := os.WriteFile(userMap, []byte(`{"dev@example.com":"octocat"}`), 0o644); err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name        string
+        fieldLabels string
+        reviewers   string
+        userMap     string
+        valid       bool
+    }{
+        {name: "nothing configured", valid: true},
+        {name: "known label fields", fieldLabels: "type, priority,component", valid: true},
+        {name: "unknown label field", fieldLabels: "type,severity", valid: false},
+        {name: "reviewers with user map", reviewers: "assignee", userMap: userMap, valid: true},
+        {name: "unknown user field", reviewers: "watcher", userMap: userMap, valid: false},
+        {name: "reviewers without user map", reviewers: "reporter", valid: false},
+        {name: "missing user map file", reviewers: "reporter", userMap: filepath.Join(t.TempDir(), "missing.json"), valid: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envFieldLabels, tt.fieldLabels)
+            t.Setenv(envReviewers, tt.reviewers)
+            t.Setenv(envAssignees, "")
+            t.Setenv(envUserMap, tt.userMap)
+
+            if err := validateFieldMappings(); (err == nil) != tt.valid {
+                t.Errorf("validateFieldMappings() error = %v, valid %v", err, tt.valid)
+            }
+        })
+    }
+}
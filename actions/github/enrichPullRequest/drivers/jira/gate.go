@@ -0,0 +1,127 @@
+package jira
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+)
+
+const envGate = "OPT_JIRA_GATE"
+const envDisallowedStatuses = "OPT_JIRA_DISALLOWED_STATUSES"
+const envAllowedProjects = "OPT_JIRA_ALLOWED_PROJECTS"
+const envDisallowedTypes = "OPT_JIRA_DISALLOWED_TYPES"
+
+// ErrGateFailed is returned when gate mode is enabled and the PR is not linked to an acceptable Jira issue. Unlike drivers.ErrNotFound
+// it stops the strategy chain, so the run fails.
+var ErrGateFailed = errors.New("jira gate failed")
+
+// gateRules are the conditions a linked issue must meet when gate mode is enabled
+type gateRules struct {
+    DisallowedStatuses []string
+    AllowedProjects    []string
+    DisallowedTypes    []string
+}
+
+func gateEnabled() bool {
+    return strings.ToLower(os.Getenv(envGate)) == "true"
+}
+
+func loadGateRules() gateRules {
+    return gateRules{
+        DisallowedStatuses: parseGateList(os.Getenv(envDisallowedStatuses)),
+        AllowedProjects:    parseGateList(os.Getenv(envAllowedProjects)),
+        DisallowedTypes:    parseGateList(os.Getenv(envDisallowedTypes)),
+    }
+}
+
+// parseGateList reads a comma-separated list of names, keeping their case for messages; names are compared without regard to case
+func parseGateList(value string) []string {
+    var names []string
+    for _, name := range strings.Split(value, ",") {
+        if name = strings.TrimSpace(name); name != "" {
+            names = append(names, name)
+        }
+    }
+
+    return names
+}
+
+func containsFold(names []string, value string) bool {
+    for _, name := range names {
+        if strings.EqualFold(name, value) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// check returns why the issue does not pass the gate, or an empty string when it does
+func (rules gateRules) check(issueKey string, jiraIssue *issue) string {
+    project, _, _ := strings.Cut(issueKey, "-")
+    if len(rules.AllowedProjects) > 0 && !containsFold(rules.AllowedProjects, project) {
+        return fmt.Sprintf("Jira issue %s belongs to project %s, which is not one of the allowed projects: %s", issueKey, project, strings.Join(rules.AllowedProjects, ", "))
+    }
+
+    if containsFold(rules.DisallowedStatuses, jiraIssue.Status) {
+        return fmt.Sprintf("Jira issue %s is in status '%s', which is not allowed for pull requests", issueKey, jiraIssue.Status)
+    }
+
+    if containsFold(rules.DisallowedTypes, jiraIssue.IssueType) {
+        return fmt.Sprintf("Jira issue %s is of type '%s', which is not allowed for pull requests", issueKey, jiraIssue.IssueType)
+    }
+
+    return ""
+}
+
+// failGate annotates the workflow run with the reason and returns the error that fails it
+func failGate(reason string) error {
+    fmt.Printf("::error title=Jira gate::%s\n", reason)
+    logger.Error(reason)
+
+    return fmt.Errorf("%w: %s", ErrGateFailed, reason)
+}
+
+// failGateOnError adds a gate failure to err when gate mode is enabled, so a run that could not reach Jira still carries the gate
+// annotation
+func failGateOnError(err error, reason string) error {
+    if err == nil || !gateEnabled() {
+        return err
+    }
+
+    return errors.Join(err, failGate(reason))
+}
+
+// enforceGate checks the linked issue against the gate rules when gate mode is enabled, fetching it if it has not been fetched yet
+func enforceGate(config Configuration) error {
+    if !gateEnabled() {
+        return nil
+    }
+
+    client, err := config.jiraClient()
+    if err != nil {
+        return failGate(fmt.Sprintf("Unable to check Jira issue %s: %v", config.IssueKey, err))
+    }
+
+    jiraIssue, err := client.getIssue(config.IssueKey)
+    if err != nil {
+        var trackerErr *drivers.TrackerError
+        if errors.As(err, &trackerErr) && trackerErr.IsNotFound {
+            return failGate(fmt.Sprintf("Jira issue %s does not exist or is not visible to the Jira user", config.IssueKey))
+        }
+
+        return failGate(fmt.Sprintf("Unable to check Jira issue %s: %v", config.IssueKey, err))
+    }
+
+    if reason := loadGateRules().check(config.IssueKey, jiraIssue); reason != "" {
+        return failGate(reason)
+    }
+
+    logger.Infof("Jira issue %s passed the gate", config.IssueKey)
+    return nil
+}
@@ -0,0 +1,112 @@
+package jira
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestGateRulesCheck(t *testing.T) {
+    rules := gateRules{
+        DisallowedStatuses: []string{"Closed", "Backlog"},
+        AllowedProjects:    []string{"PROJ", "OPS"},
+        DisallowedTypes:    []string{"Epic"},
+    }
+
+    tests := []struct {
+        name     string
+        issueKey string
+        issue    *issue
+        passes   bool
+    }{
+        {name: "allowed issue", issueKey: "PROJ-12", issue: &issue{Status: "In Progress", IssueType: "Story"}, passes: true},
+        {name: "project compared without case", issueKey: "ops-3", issue: &issue{Status: "In Progress", IssueType: "Bug"}, passes: true},
+        {name: "project not allowed", issueKey: "MKT-7", issue: &issue{Status: "In Progress", IssueType: "Story"}},
+        {name: "disallowed status", issueKey: "PROJ-12", issue: &issue{Status: "backlog", IssueType: "Story"}},
+        {name: "disallowed type", issueKey: "PROJ-12", issue: &issue{Status: "In Progress", IssueType: "Epic"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            reason := rules.check(tt.issueKey, tt.issue)
+            if (reason == "") != tt.passes {
+                t.Errorf("check() = %q, want passes = %v", reason, tt.passes)
+            }
+        })
+    }
+
+    if reason := (gateRules{}).check("ANY-1", &issue{Status: "Closed", IssueType: "Epic"}); reason != "" {
+        t.Errorf("check() with no rules = %q, want the issue to pass", reason)
+    }
+}
+
+func TestEnforceGate(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/rest/api/3/issue/PROJ-12":
+            w.Write([]byte(`{"key":"PROJ-12","fields":{"summary":"Add export","status":{"name":"In Progress"},"issuetype":{"name":"Story"}}}`))
+        case "/rest/api/3/issue/PROJ-13":
+            w.Write([]byte(`{"key":"PROJ-13","fields":{"summary":"Old work","status":{"name":"Closed"},"issuetype":{"name":"Story"}}}`))
+        default:
+            w.WriteHeader(http.StatusNotFound)
+            w.Write([]byte(`{"errorMessages":["Issue does not exist or you do not have permission to see it."]}`))
+        }
+    }))
+    defer server.Close()
+
+    tests := []struct {
+        name     string
+        gate     string
+        issueKey string
+        wantErr  bool
+    }{
+        {name: "gate disabled", gate: "", issueKey: "PROJ-99"},
+        {name: "issue passes", gate: "true", issueKey: "PROJ-12"},
+        {name: "disallowed status", gate: "true", issueKey: "PROJ-13", wantErr: true},
+        {name: "missing issue", gate: "true", issueKey: "PROJ-99", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envGate, tt.gate)
+            t.Setenv(envDisallowedStatuses, "Closed")
+
+            config := Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", IssueKey: tt.issueKey, DeploymentType: deploymentCloud}
+
+            err := enforceGate(config)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("enforceGate() error = %v, wantErr %v", err, tt.wantErr)
+            }
+
+            if err != nil && !errors.Is(err, ErrGateFailed) {
+                t.Errorf("enforceGate() error = %v, want ErrGateFailed", err)
+            }
+        })
+    }
+}
+
+func TestFormatGateFailsWhenJiraIsUnavailable(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer server.Close()
+
+    t.Setenv(envGate, "true")
+    t.Setenv(envURL, server.URL)
+    t.Setenv(envEmail, "dev@example.com")
+    t.Setenv(envToken, "token")
+    t.Setenv(envTimeout, "100ms")
+
+    gh := &fakeGitHub{branchName: "feature/PROJ-12-add-export"}
+
+    err := Format(context.Background(), gh)
+    if !errors.Is(err, ErrGateFailed) {
+        t.Fatalf("Format() error = %v, want ErrGateFailed", err)
+    }
+
+    if len(gh.comments) != 1 {
+        t.Errorf("Format() posted %d comments, want 1 explaining the failure", len(gh.comments))
+    }
+}
@@ -2,6 +2,7 @@ package jira
 
 import (
     "context"
+    "encoding/json"
     "errors"
     "fmt"
     "net/http"
@@ -9,211 +10,691 @@ import (
     "strings"
 
     "github.com/EncoreDigitalGroup/golib/logger"
+    "github.com/ctreminiom/go-atlassian/jira/v2"
     "github.com/ctreminiom/go-atlassian/jira/v3"
     "github.com/ctreminiom/go-atlassian/pkg/infra/models"
 
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
     branchname "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/branch_name"
     "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
 )
 
+const Name = "jira"
+
+const envURL = "OPT_JIRA_URL"
+const envEmail = "OPT_JIRA_EMAIL"
+const envToken = "OPT_JIRA_TOKEN"
+const envDeploymentType = "OPT_JIRA_DEPLOYMENT_TYPE"
+
+const deploymentCloud = "cloud"
+const deploymentServer = "server"
+
 type Configuration struct {
-    Enable   bool
-    URL      string
-    Email    string
-    Token    string
-    IssueKey string
+    Enable         bool
+    URL            string
+    Email          string
+    Token          string
+    IssueKey       string
+    DeploymentType string
+
+    // Context carries the OPT_JIRA_TIMEOUT deadline shared by every Jira request in the run
+    Context context.Context
+
+    // CustomFields are rendered into the description of the primary issue
+    CustomFields []customField
+
+    // client is shared by every Jira request in the run
+    client issueClient
 }
 
 type Information struct {
     ParentPrefix string
+    ParentKey    string
     Title        string
     Description  string
-    HasJiraInfo  bool
-    AuthFailure  bool
+    IssueType    string
+    Status       string
+    Sprint       string
+
+    // issue is kept for the optional field mappings
+    issue *issue
+}
+
+// issue holds the fields the driver uses, whichever REST API version returned them
+type issue struct {
+    Summary       string
+    Description   string
+    IssueType     string
+    Status        string
+    ParentKey     string
+    ParentType    string
+    ParentSummary string
+    Sprint        string
+    Priority      string
+    Components    []string
+    FixVersions   []string
+    Assignee      *jiraUser
+    Reporter      *jiraUser
+    Transitions   []*models.IssueTransitionScheme
+
+    // rawFields keeps the custom field values, which are only rendered for the primary issue, with renderText for their text
+    rawFields  map[string]json.RawMessage
+    renderText func(string) string
+}
+
+// issueClient fetches issues from Jira Cloud (REST API v3) or Jira Server/Data Center (REST API v2)
+type issueClient interface {
+    getIssue(issueKey string) (*issue, error)
+    getTransitions(issueKey string) ([]*models.IssueTransitionScheme, error)
+    moveIssue(issueKey string, transitionID string) error
+    linkPullRequest(issueKey string, link *models.RemoteLinkScheme) (bool, error)
+    addPullRequestComment(issueKey string, summary pullRequestSummary) error
+}
+
+// restClient implements issueClient for both REST API versions; api supplies the calls whose go-atlassian types differ
+type restClient struct {
+    ctx          context.Context
+    api          restAPI
+    customFields []customField
 }
 
-type JiraError struct {
-    IsAuthFailure bool
-    OriginalError error
+// restAPI wraps the go-atlassian calls that differ between Jira Cloud (REST API v3, ADF) and Jira Server/Data Center (REST API v2,
+// wiki markup), along with the renderers for each version's rich text
+type restAPI interface {
+    getIssue(ctx context.Context, issueKey string, fields []string, expand []string) (*models.ResponseScheme, error)
+    getTransitions(ctx context.Context, issueKey string) (*models.IssueTransitionsScheme, *models.ResponseScheme, error)
+    moveIssue(ctx context.Context, issueKey string, transitionID string) (*models.ResponseScheme, error)
+    getRemoteLinks(ctx context.Context, issueKey string) ([]*models.RemoteLinkScheme, *models.ResponseScheme, error)
+    createRemoteLink(ctx context.Context, issueKey string, link *models.RemoteLinkScheme) (*models.ResponseScheme, error)
+    addComment(ctx context.Context, issueKey string, summary pullRequestSummary) (*models.ResponseScheme, error)
+    describe(description json.RawMessage) string
+    renderText(text string) string
 }
 
-func (e *JiraError) Error() string {
-    return e.OriginalError.Error()
+type cloudClient struct {
+    client *v3.Client
 }
 
-func Format(gh github.GitHub) {
-    if jiraLabelSyncEnabled() && gh.HasLabel(jiraLabelSyncName()) {
-        logger.Info("PR already has '" + jiraLabelSyncName() + "' label, skipping Jira sync")
-        return
+type serverClient struct {
+    client *v2.Client
+}
+
+// cachingClient remembers each issue it fetches, so the steps of a run that need the same issue share one request
+type cachingClient struct {
+    issueClient
+    issues map[string]*issue
+}
+
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    deploymentType, err := jiraDeploymentType()
+    if err != nil {
+        return err
     }
 
-    branchName, err := gh.GetBranchName()
+    if _, err := jiraTimeout(); err != nil {
+        return err
+    }
+
+    if err := validateFieldMappings(); err != nil {
+        return err
+    }
+
+    var missing []string
+    for _, envVar := range requiredEnvVars(deploymentType) {
+        if os.Getenv(envVar) == "" {
+            missing = append(missing, envVar)
+        }
+    }
+
+    if len(missing) > 0 {
+        return fmt.Errorf("%s must be set when configured strategy is '%s'", strings.Join(missing, ", "), Name)
+    }
+
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    timeout, err := jiraTimeout()
     if err != nil {
-        logger.Error(err.Error())
-        os.Exit(1)
+        return err
     }
 
-    envUrl := os.Getenv("OPT_JIRA_URL")
-    if envUrl == "" {
-        logger.Error("OPT_JIRA_URL is not set")
-        os.Exit(1)
+    // Every Jira request in the run, retries included, shares one deadline
+    jiraCtx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
+
+    options := syncOptions()
+    synced, err := options.AlreadySynced(ctx, gh)
+    if err != nil {
+        return err
+    }
+
+    if synced {
+        // The gate, transitions, and PR links follow the PR lifecycle, so they still run once the issue has been synced
+        if gateEnabled() || followsPullRequest() {
+            config, err := newGatedConfiguration(ctx, jiraCtx, gh)
+            if err != nil {
+                return err
+            }
+
+            if err := enforceGate(config); err != nil {
+                return err
+            }
+
+            return followPullRequest(ctx, gh, config)
+        }
+
+        return nil
+    }
+
+    config, err := newGatedConfiguration(ctx, jiraCtx, gh)
+    if err != nil {
+        return err
+    }
+
+    issueKey := config.IssueKey
+
+    jira, err := getJiraInfo(config)
+    if err != nil {
+        return fetchFailed(ctx, gh, config, err)
     }
 
-    envEmail := os.Getenv("OPT_JIRA_EMAIL")
-    if envEmail == "" {
-        logger.Error("OPT_JIRA_EMAIL is not set")
-        os.Exit(1)
+    if err := enforceGate(config); err != nil {
+        return err
     }
 
-    envToken := os.Getenv("OPT_JIRA_TOKEN")
-    if envToken == "" {
-        logger.Error("OPT_JIRA_TOKEN is not set")
-        os.Exit(1)
+    issue := drivers.Issue{
+        Key:          issueKey,
+        Title:        jira.Title,
+        Description:  jira.Description,
+        ParentPrefix: jira.ParentPrefix,
+        Type:         jira.IssueType,
+        Sprint:       jira.Sprint,
+    }
+
+    if jira.issue != nil {
+        issue.EpicKey, issue.EpicName = jira.issue.epic()
+        issue.EpicInTitle = contextEnabled("title")
+
+        if header := contextHeader(config.URL, jira.issue); header != "" && contextEnabled("description") {
+            issue.Description = strings.TrimSpace(header + "\n\n" + issue.Description)
+        }
+    }
+
+    related, err := relatedIssues(ctx, gh, config, jira.ParentKey)
+    if err != nil {
+        return err
+    }
+
+    if len(related) > 0 {
+        for _, relatedIssue := range related {
+            if relatedIssue.InTitle {
+                issue.RelatedKeys = append(issue.RelatedKeys, relatedIssue.Key)
+            }
+        }
+
+        primary := relatedIssue{Key: issueKey, Summary: jira.Title, IssueType: jira.IssueType, Status: jira.Status}
+        issue.Description = strings.TrimSpace(issue.Description + "\n\n" + linkedIssuesTable(config.URL, append([]relatedIssue{primary}, related...)))
+    }
+
+    if err := drivers.Sync(ctx, gh, issue, options); err != nil {
+        return err
+    }
+
+    // The optional updates are independent, so one failing does not stop the others
+    return errors.Join(
+        syncFieldMappings(ctx, gh, jira.issue),
+        syncContextLabels(ctx, gh, jira.issue),
+        followPullRequest(ctx, gh, config),
+    )
+}
+
+// fetchFailed explains a failure to fetch the issue like the other drivers, and fails the gate when gate mode is enabled
+func fetchFailed(ctx context.Context, gh github.GitHub, config Configuration, err error) error {
+    var trackerErr *drivers.TrackerError
+    isTrackerErr := errors.As(err, &trackerErr)
+
+    if isTrackerErr && trackerErr.IsNotFound && gateEnabled() {
+        return failGate(fmt.Sprintf("Jira issue %s does not exist or is not visible to the Jira user", config.IssueKey))
+    }
+
+    err = drivers.FetchFailed(ctx, gh, drivers.Tracker{
+        Name: "Jira",
+        Item: "issue",
+        Solutions: []string{
+            "Check that `" + strings.Join(requiredEnvVars(config.DeploymentType), "`, `") + "` environment variables are set correctly",
+            "Verify that the Jira " + tokenKind(config.DeploymentType) + " is still valid",
+            "Ensure the Jira user has permission to access the issue: `" + config.IssueKey + "`",
+        },
+    }, config.IssueKey, err)
+
+    if errors.Is(err, drivers.ErrNotFound) {
+        return err
+    }
+
+    reason := "Jira could not be reached or returned an error"
+    if isTrackerErr && trackerErr.IsAuthFailure {
+        reason = "Jira authentication failed"
+    }
+
+    return failGateOnError(err, fmt.Sprintf("Unable to check Jira issue %s: %s", config.IssueKey, reason))
+}
+
+func followsPullRequest() bool {
+    return transitionsConfigured() || linkPullRequestEnabled()
+}
+
+// followPullRequest applies the optional Jira updates that track the pull request through its lifecycle
+func followPullRequest(ctx context.Context, gh github.GitHub, config Configuration) error {
+    return errors.Join(transitionIssue(ctx, gh, config), linkPullRequest(ctx, gh, config))
+}
+
+// newGatedConfiguration fails the gate, rather than letting the next strategy run, when gate mode is enabled and the branch has no issue key
+func newGatedConfiguration(ctx context.Context, jiraCtx context.Context, gh github.GitHub) (Configuration, error) {
+    config, err := newConfiguration(ctx, jiraCtx, gh)
+    if errors.Is(err, drivers.ErrNotFound) && gateEnabled() {
+        branchName, _ := gh.GetBranchName(ctx)
+        return Configuration{}, failGate(fmt.Sprintf("Branch %s does not reference a Jira issue", branchName))
+    }
+
+    return config, err
+}
+
+// newConfiguration reads the Jira settings for the branch's issue; Jira requests made through it are bound to jiraCtx
+func newConfiguration(ctx context.Context, jiraCtx context.Context, gh github.GitHub) (Configuration, error) {
+    branchName, err := gh.GetBranchName(ctx)
+    if err != nil {
+        return Configuration{}, err
     }
 
     issueKey, err := branchname.GetIssueKeyFromBranchName(branchName)
     if err != nil {
-        logger.Error(err.Error())
-        os.Exit(1)
+        return Configuration{}, err
     }
 
     if issueKey == "" {
-        logger.Error("Issue key is empty")
-        return
+        return Configuration{}, fmt.Errorf("%w: branch %s does not contain an issue key", drivers.ErrNotFound, branchName)
+    }
+
+    deploymentType, err := jiraDeploymentType()
+    if err != nil {
+        return Configuration{}, err
     }
 
     config := Configuration{
-        Enable:   true,
-        URL:      envUrl,
-        Email:    envEmail,
-        Token:    envToken,
-        IssueKey: issueKey,
+        Enable:         true,
+        URL:            os.Getenv(envURL),
+        Email:          os.Getenv(envEmail),
+        Token:          os.Getenv(envToken),
+        IssueKey:       issueKey,
+        DeploymentType: deploymentType,
+        Context:        jiraCtx,
+        CustomFields:   configuredCustomFields(),
     }
 
-    jira := getJiraInfo(config)
+    // A missing URL is reported when the issue is fetched, so a client that cannot be created yet is not an error here
+    if client, err := createJiraClient(config); err == nil {
+        config.client = &cachingClient{issueClient: client, issues: make(map[string]*issue)}
+    }
+
+    return config, nil
+}
 
-    if jira.AuthFailure {
-        logger.Errorf("Jira authentication failed")
+// jiraClient returns the client shared by the run, or a new client for a configuration built without one
+func (config Configuration) jiraClient() (issueClient, error) {
+    if config.client != nil {
+        return config.client, nil
+    }
 
-        comment := "**Jira Authentication Failed**\n\n" +
-            "Unable to authenticate with Jira to fetch issue information. " +
-            "Please verify that the Jira credentials (URL, email, and token) are correctly configured and that the token has not expired.\n\n" +
-            "**Possible solutions:**\n" +
-            "- Check that `OPT_JIRA_URL`, `OPT_JIRA_EMAIL`, and `OPT_JIRA_TOKEN` environment variables are set correctly\n" +
-            "- Verify that the Jira API token is still valid\n" +
-            "- Ensure the Jira user has permission to access the issue: `" + issueKey + "`"
+    return createJiraClient(config)
+}
 
-        gh.AddPRComment(comment)
-        return
+func (c *cachingClient) getIssue(issueKey string) (*issue, error) {
+    if cached, ok := c.issues[issueKey]; ok {
+        return cached, nil
     }
 
-    if config.Enable && !jira.HasJiraInfo {
-        logger.Errorf("Failed to get Jira info")
-        comment := "Failed to get information from Jira.\n\n" +
-            "Please check the GitHub Action logs for specific error information."
+    result, err := c.issueClient.getIssue(issueKey)
+    if err != nil {
+        return nil, err
+    }
 
-        gh.AddPRComment(comment)
+    c.issues[issueKey] = result
+    return result, nil
+}
 
-        return
+func createJiraClient(config Configuration) (issueClient, error) {
+    ctx := config.Context
+    if ctx == nil {
+        ctx = context.Background()
     }
 
-    newPRTitle := gh.ApplyFormatting(issueKey, jira.Title)
+    httpClient := &http.Client{Transport: newRetryTransport()}
 
-    if jira.ParentPrefix != "" {
-        newPRTitle = fmt.Sprintf("[%s]%s", jira.ParentPrefix, newPRTitle)
+    if config.DeploymentType == deploymentServer {
+        client, err := v2.New(httpClient, config.URL)
+        if err != nil {
+            return nil, err
+        }
+
+        client.Auth.SetBearerToken(config.Token)
+        return restClient{ctx: ctx, api: serverClient{client: client}, customFields: config.CustomFields}, nil
     }
 
-    if jiraDescriptionSyncEnabled() {
-        logger.Info("Updating PR title and description from Jira issue")
-        gh.UpdatePR(newPRTitle, jira.Description)
-    } else {
-        logger.Info("Updating PR title from Jira issue")
-        gh.UpdatePRTitle(newPRTitle)
+    client, err := v3.New(httpClient, config.URL)
+    if err != nil {
+        return nil, err
     }
 
-    if jiraLabelSyncEnabled() {
-        gh.EnsureLabelExists(jiraLabelSyncName(), "Indicates that Jira synchronization has been completed for this PR", "0052cc")
-        gh.AddLabelToPR(jiraLabelSyncName())
+    client.Auth.SetBasicAuth(config.Email, config.Token)
+    return restClient{ctx: ctx, api: cloudClient{client: client}, customFields: config.CustomFields}, nil
+}
+
+// issueFields lists the fields the driver reads, so Jira does not return every field on the issue
+func issueFields(customFields []customField) []string {
+    fields := []string{"summary", "description", "issuetype", "status", "parent", "priority", "components", "fixVersions", "assignee", "reporter", sprintField()}
+    for _, field := range customFields {
+        fields = append(fields, field.ID)
     }
+
+    return fields
 }
 
-func createJiraClient(jiraURL, jiraEmail, jiraToken string) (*v3.Client, error) {
-    client, err := v3.New(nil, jiraURL)
+// issueExpand requests the available transitions with the issue when transitions are configured, saving a separate request
+func issueExpand() []string {
+    if transitionsConfigured() {
+        return []string{"transitions"}
+    }
+
+    return nil
+}
+
+func (c restClient) getIssue(issueKey string) (*issue, error) {
+    response, err := c.api.getIssue(c.ctx, issueKey, issueFields(c.customFields), issueExpand())
     if err != nil {
-        return nil, err
+        return nil, newJiraError(issueKey, response, err)
     }
 
-    client.Auth.SetBasicAuth(jiraEmail, jiraToken)
-    return client, nil
+    return newIssue(response.Bytes.Bytes(), c.api.describe, c.api.renderText)
 }
 
-func getCurrentIssueInfo(client *v3.Client, issueKey string) (*models.IssueScheme, error) {
-    issue, response, err := client.Issue.Get(context.Background(), issueKey, nil, nil)
+func (c restClient) getTransitions(issueKey string) ([]*models.IssueTransitionScheme, error) {
+    transitions, response, err := c.api.getTransitions(c.ctx, issueKey)
     if err != nil {
-        isAuthFailure := response != nil && response.StatusCode == http.StatusUnauthorized
-        return nil, &JiraError{
-            IsAuthFailure: isAuthFailure,
-            OriginalError: fmt.Errorf("failed to fetch Jira issue %s: %v", issueKey, err),
-        }
+        return nil, newJiraError(issueKey, response, err)
     }
 
-    return issue, nil
+    return transitions.Transitions, nil
 }
 
-func getParentIssuePrefix(client *v3.Client, issueKey string) (string, error) {
-    issue, _, err := client.Issue.Get(context.Background(), issueKey, nil, nil)
+func (c restClient) moveIssue(issueKey string, transitionID string) error {
+    response, err := c.api.moveIssue(c.ctx, issueKey, transitionID)
     if err != nil {
-        return "", fmt.Errorf("failed to fetch Jira issue %s: %v", issueKey, err)
+        return newJiraError(issueKey, response, err)
+    }
+
+    return nil
+}
+
+func (c restClient) linkPullRequest(issueKey string, link *models.RemoteLinkScheme) (bool, error) {
+    // Jira answers a globalId lookup with a single object rather than a list, so every link is listed and searched instead
+    existing, _, lookupErr := c.api.getRemoteLinks(c.ctx, issueKey)
+
+    if response, err := c.api.createRemoteLink(c.ctx, issueKey, link); err != nil {
+        return false, newJiraError(issueKey, response, err)
+    }
+
+    // When the lookup failed the link may already have existed, so it is not reported as new
+    return lookupErr == nil && !hasRemoteLink(existing, link.GlobalID), nil
+}
+
+func (c restClient) addPullRequestComment(issueKey string, summary pullRequestSummary) error {
+    if response, err := c.api.addComment(c.ctx, issueKey, summary); err != nil {
+        return newJiraError(issueKey, response, err)
+    }
+
+    return nil
+}
+
+// newIssue maps the fields both REST API versions return in the same shape; describe renders the description, which is ADF on Jira
+// Cloud and wiki markup on Jira Server
+func newIssue(body []byte, describe func(json.RawMessage) string, renderText func(string) string) (*issue, error) {
+    var payload struct {
+        Fields struct {
+            Summary     string                    `json:"summary"`
+            Description json.RawMessage           `json:"description"`
+            IssueType   *models.IssueTypeScheme   `json:"issuetype"`
+            Status      *models.StatusScheme      `json:"status"`
+            Parent      *models.ParentScheme      `json:"parent"`
+            Priority    *models.PriorityScheme    `json:"priority"`
+            Components  []*models.ComponentScheme `json:"components"`
+            FixVersions []*models.VersionScheme   `json:"fixVersions"`
+            Assignee    *models.UserScheme        `json:"assignee"`
+            Reporter    *models.UserScheme        `json:"reporter"`
+        } `json:"fields"`
+        Transitions []*models.IssueTransitionScheme `json:"transitions"`
+    }
+
+    if err := json.Unmarshal(body, &payload); err != nil {
+        return nil, fmt.Errorf("failed to read Jira issue: %v", err)
     }
 
-    if issue.Fields.Parent == nil {
+    fields := payload.Fields
+    result := &issue{
+        Summary:     fields.Summary,
+        Description: describe(fields.Description),
+        Transitions: payload.Transitions,
+        renderText:  renderText,
+    }
+
+    rawFields := rawIssueFields(body)
+    result.rawFields = rawFields
+    result.ParentType, result.ParentSummary = parentDetails(rawFields)
+    result.Sprint = activeSprint(rawFields[sprintField()])
+
+    if fields.IssueType != nil {
+        result.IssueType = fields.IssueType.Name
+    }
+
+    if fields.Status != nil {
+        result.Status = fields.Status.Name
+    }
+
+    if fields.Parent != nil {
+        result.ParentKey = fields.Parent.Key
+    }
+
+    if fields.Priority != nil {
+        result.Priority = fields.Priority.Name
+    }
+
+    result.Components = componentNames(fields.Components)
+    result.FixVersions = versionNames(fields.FixVersions)
+    result.Assignee = newJiraUser(fields.Assignee)
+    result.Reporter = newJiraUser(fields.Reporter)
+
+    return result, nil
+}
+
+func (c cloudClient) getIssue(ctx context.Context, issueKey string, fields []string, expand []string) (*models.ResponseScheme, error) {
+    _, response, err := c.client.Issue.Get(ctx, issueKey, fields, expand)
+    return response, err
+}
+
+func (c cloudClient) getTransitions(ctx context.Context, issueKey string) (*models.IssueTransitionsScheme, *models.ResponseScheme, error) {
+    return c.client.Issue.Transitions(ctx, issueKey)
+}
+
+func (c cloudClient) moveIssue(ctx context.Context, issueKey string, transitionID string) (*models.ResponseScheme, error) {
+    return c.client.Issue.Move(ctx, issueKey, transitionID, nil)
+}
+
+func (c cloudClient) getRemoteLinks(ctx context.Context, issueKey string) ([]*models.RemoteLinkScheme, *models.ResponseScheme, error) {
+    return c.client.Issue.Link.Remote.Gets(ctx, issueKey, "")
+}
+
+func (c cloudClient) createRemoteLink(ctx context.Context, issueKey string, link *models.RemoteLinkScheme) (*models.ResponseScheme, error) {
+    _, response, err := c.client.Issue.Link.Remote.Create(ctx, issueKey, link)
+    return response, err
+}
+
+func (c cloudClient) addComment(ctx context.Context, issueKey string, summary pullRequestSummary) (*models.ResponseScheme, error) {
+    payload := &models.CommentPayloadScheme{Body: adfPullRequestComment(summary)}
+    _, response, err := c.client.Issue.Comment.Add(ctx, issueKey, payload, nil)
+    return response, err
+}
+
+// describe renders a Jira Cloud description, which arrives as an ADF document
+func (cloudClient) describe(description json.RawMessage) string {
+    var document *models.CommentNodeScheme
+    if len(description) > 0 {
+        if err := json.Unmarshal(description, &document); err != nil {
+            logger.Errorf("Failed to read Jira description: %v", err)
+        }
+    }
+
+    return ADFToMarkdown(document)
+}
+
+// renderText leaves Jira Cloud text as written; its rich text fields arrive as ADF rather than as markup
+func (cloudClient) renderText(text string) string {
+    return strings.TrimSpace(text)
+}
+
+func (c serverClient) getIssue(ctx context.Context, issueKey string, fields []string, expand []string) (*models.ResponseScheme, error) {
+    _, response, err := c.client.Issue.Get(ctx, issueKey, fields, expand)
+    return response, err
+}
+
+func (c serverClient) getTransitions(ctx context.Context, issueKey string) (*models.IssueTransitionsScheme, *models.ResponseScheme, error) {
+    return c.client.Issue.Transitions(ctx, issueKey)
+}
+
+func (c serverClient) moveIssue(ctx context.Context, issueKey string, transitionID string) (*models.ResponseScheme, error) {
+    return c.client.Issue.Move(ctx, issueKey, transitionID, nil)
+}
+
+func (c serverClient) getRemoteLinks(ctx context.Context, issueKey string) ([]*models.RemoteLinkScheme, *models.ResponseScheme, error) {
+    return c.client.Issue.Link.Remote.Gets(ctx, issueKey, "")
+}
+
+func (c serverClient) createRemoteLink(ctx context.Context, issueKey string, link *models.RemoteLinkScheme) (*models.ResponseScheme, error) {
+    _, response, err := c.client.Issue.Link.Remote.Create(ctx, issueKey, link)
+    return response, err
+}
+
+func (c serverClient) addComment(ctx context.Context, issueKey string, summary pullRequestSummary) (*models.ResponseScheme, error) {
+    payload := &models.CommentPayloadSchemeV2{Body: wikiPullRequestComment(summary)}
+    _, response, err := c.client.Issue.Comment.Add(ctx, issueKey, payload, nil)
+    return response, err
+}
+
+// describe renders a Jira Server description, which arrives as wiki markup
+func (serverClient) describe(description json.RawMessage) string {
+    var text string
+    if len(description) > 0 {
+        if err := json.Unmarshal(description, &text); err != nil {
+            logger.Errorf("Failed to read Jira description: %v", err)
+        }
+    }
+
+    return WikiToMarkdown(text)
+}
+
+func (serverClient) renderText(text string) string {
+    return WikiToMarkdown(text)
+}
+
+func hasRemoteLink(links []*models.RemoteLinkScheme, globalID string) bool {
+    for _, link := range links {
+        if link.GlobalID == globalID {
+            return true
+        }
+    }
+
+    return false
+}
+
+func newJiraError(issueKey string, response *models.ResponseScheme, err error) error {
+    var statusCode int
+    if response != nil && response.Response != nil {
+        statusCode = response.StatusCode
+    }
+
+    return &drivers.TrackerError{
+        IsAuthFailure: statusCode == http.StatusUnauthorized,
+        IsNotFound:    statusCode == http.StatusNotFound,
+        OriginalError: fmt.Errorf("failed to fetch Jira issue %s: %v", issueKey, err),
+    }
+}
+
+func getParentIssuePrefix(client issueClient, jiraIssue *issue) (string, error) {
+    if jiraIssue.ParentKey == "" {
         return "", nil
     }
 
-    parentIssue, _, err := client.Issue.Get(context.Background(), issue.Fields.Parent.Key, nil, nil)
-    if err != nil {
-        return "", fmt.Errorf("failed to fetch parent Jira issue %s: %v", issue.Fields.Parent.Key, err)
+    // Jira embeds the parent's type in the issue, so the parent is only fetched when the response left it out
+    if jiraIssue.ParentType == "" {
+        parentIssue, err := client.getIssue(jiraIssue.ParentKey)
+        if err != nil {
+            return "", fmt.Errorf("failed to fetch parent Jira issue %s: %v", jiraIssue.ParentKey, err)
+        }
+
+        jiraIssue.ParentType = parentIssue.IssueType
+        jiraIssue.ParentSummary = parentIssue.Summary
     }
 
-    if strings.ToLower(parentIssue.Fields.IssueType.Name) == "epic" {
+    if strings.ToLower(jiraIssue.ParentType) == "epic" {
         return "", nil
     }
 
-    return fmt.Sprintf("%s", issue.Fields.Parent.Key), nil
+    return jiraIssue.ParentKey, nil
 }
 
-func getJiraInfo(config Configuration) Information {
+func getJiraInfo(config Configuration) (Information, error) {
     if !config.Enable {
-        return Information{HasJiraInfo: false}
+        return Information{}, errors.New("jira sync is not enabled")
     }
 
-    if config.URL == "" || config.Email == "" || config.Token == "" {
-        logger.Error("OPT_JIRA_URL, OPT_JIRA_EMAIL, and OPT_JIRA_TOKEN must be set when configured strategy is 'jira'.")
-        return Information{HasJiraInfo: false}
+    if config.URL == "" || config.Token == "" || (config.DeploymentType != deploymentServer && config.Email == "") {
+        return Information{}, fmt.Errorf("%s must be set when configured strategy is '%s'", strings.Join(requiredEnvVars(config.DeploymentType), ", "), Name)
     }
 
-    client, err := createJiraClient(config.URL, config.Email, config.Token)
+    client, err := config.jiraClient()
     if err != nil {
-        logger.Errorf("Failed to create Jira client: %v", err)
-        return Information{HasJiraInfo: false}
+        return Information{}, fmt.Errorf("failed to create Jira client: %w", err)
     }
 
-    result := Information{HasJiraInfo: true}
-
-    jiraIssue, err := getCurrentIssueInfo(client, config.IssueKey)
+    jiraIssue, err := client.getIssue(config.IssueKey)
     if err != nil {
-        logger.Errorf("Failed to get current issue info: %v", err)
-        var jiraErr *JiraError
-        if errors.As(err, &jiraErr) && jiraErr.IsAuthFailure {
-            return Information{HasJiraInfo: false, AuthFailure: true}
-        }
-
-        return Information{HasJiraInfo: false}
+        return Information{}, err
     }
-    result.Title = jiraIssue.Fields.Summary
+
+    var result Information
+    result.Title = jiraIssue.Summary
+    result.Description = withCustomFieldSections(jiraIssue.Description, config.CustomFields, jiraIssue.customFieldValues(config.CustomFields))
+    result.IssueType = jiraIssue.IssueType
+    result.Status = jiraIssue.Status
+    result.ParentKey = jiraIssue.ParentKey
+    result.Sprint = jiraIssue.Sprint
+    result.issue = jiraIssue
 
     // Get parent issue prefix if applicable
-    parentPrefix, err := getParentIssuePrefix(client, config.IssueKey)
+    parentPrefix, err := getParentIssuePrefix(client, jiraIssue)
     if err != nil {
         logger.Errorf("Failed to get parent issue info: %v", err)
         // Don't fail completely, just continue without parent prefix
@@ -221,27 +702,37 @@ func getJiraInfo(config Configuration) Information {
         result.ParentPrefix = parentPrefix
     }
 
-    if jiraIssue.Fields.Description != nil {
-        result.Description = jiraIssue.Fields.Description.Text
-    }
-
-    return result
+    return result, nil
 }
 
-func jiraLabelSyncEnabled() bool {
-    return strings.ToLower(os.Getenv("OPT_ENABLE_JIRA_SYNC_LABEL")) == "true"
+func syncOptions() drivers.SyncOptions {
+    return drivers.NewSyncOptions("Jira", "JIRA", "jira-sync-complete")
 }
 
-func jiraDescriptionSyncEnabled() bool {
-    return strings.ToLower(os.Getenv("OPT_ENABLE_JIRA_SYNC_DESCRIPTION")) == "true"
+// jiraDeploymentType returns "cloud" or "server"; Data Center uses the same API as Server
+func jiraDeploymentType() (string, error) {
+    switch strings.ToLower(os.Getenv(envDeploymentType)) {
+    case "", deploymentCloud:
+        return deploymentCloud, nil
+    case deploymentServer, "datacenter", "data-center":
+        return deploymentServer, nil
+    default:
+        return "", fmt.Errorf("invalid %s %q; expected \"cloud\" or \"server\"", envDeploymentType, os.Getenv(envDeploymentType))
+    }
 }
 
-func jiraLabelSyncName() string {
-    label := os.Getenv("OPT_JIRA_SYNC_LABEL")
+func requiredEnvVars(deploymentType string) []string {
+    if deploymentType == deploymentServer {
+        return []string{envURL, envToken}
+    }
+
+    return []string{envURL, envEmail, envToken}
+}
 
-    if label == "" {
-        return "jira-sync-complete"
+func tokenKind(deploymentType string) string {
+    if deploymentType == deploymentServer {
+        return "Personal Access Token"
     }
 
-    return label
+    return "API token"
 }
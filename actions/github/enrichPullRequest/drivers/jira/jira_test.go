@@ -0,0 +1,70 @@
+package jira
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestValidate(t *testing.T) {
+    tests := []struct {
+        name           string
+        deploymentType string
+        email          string
+        valid          bool
+    }{
+        {name: "cloud with email", deploymentType: "", email: "dev@example.com", valid: true},
+        {name: "cloud without email", deploymentType: "cloud", email: "", valid: false},
+        {name: "server without email", deploymentType: "server", email: "", valid: true},
+        {name: "data center alias", deploymentType: "DataCenter", email: "", valid: true},
+        {name: "unknown deployment type", deploymentType: "onprem", email: "dev@example.com", valid: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envURL, "https://jira.example.com")
+            t.Setenv(envToken, "token")
+            t.Setenv(envEmail, tt.email)
+            t.Setenv(envDeploymentType, tt.deploymentType)
+
+            if err := (driver{}).Validate(); (err == nil) != tt.valid {
+                t.Errorf("Validate() error = %v, valid %v", err, tt.valid)
+            }
+        })
+    }
+}
+
+func TestNewIssue(t *testing.T) {
+    tests := []struct {
+        name        string
+        api         restAPI
+        description string
+    }{
+        {name: "cloud", api: cloudClient{}, description: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"Export all records"}]}]}`},
+        {name: "server", api: serverClient{}, description: `"Export all records"`},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            body := []byte(`{"fields":{"summary":"Add export","description":` + tt.description + `,"issuetype":{"name":"Story"},"status":{"name":"In Progress"},"parent":{"key":"PROJ-1","fields":{"summary":"Billing","issuetype":{"name":"Epic"}}},"priority":{"name":"High"},"components":[{"name":"API"}]}}`)
+
+            result, err := newIssue(body, tt.api.describe, tt.api.renderText)
+            if err != nil {
+                t.Fatalf("newIssue() error = %v", err)
+            }
+
+            actual := []string{result.Summary, result.Description, result.IssueType, result.Status, result.ParentKey, result.ParentType, result.ParentSummary, result.Priority}
+            expected := []string{"Add export", "Export all records", "Story", "In Progress", "PROJ-1", "Epic", "Billing", "High"}
+            if !reflect.DeepEqual(actual, expected) || !reflect.DeepEqual(result.Components, []string{"API"}) {
+                t.Errorf("newIssue() = %+v", result)
+            }
+        })
+    }
+}
+
+func TestSyncOptionsLabelName(t *testing.T) {
+    t.Setenv("OPT_JIRA_SYNC_LABEL_NAME", "jira-synced")
+
+    if actual := syncOptions().LabelName; actual != "jira-synced" {
+        t.Errorf("syncOptions().LabelName = %q, want %q", actual, "jira-synced")
+    }
+}
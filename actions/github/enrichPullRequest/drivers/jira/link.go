@@ -0,0 +1,180 @@
+package jira
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const envLinkPullRequest = "OPT_JIRA_LINK_PULL_REQUEST"
+const envPullRequestComment = "OPT_JIRA_PULL_REQUEST_COMMENT"
+
+// pullRequestSummary describes the pull request in the Jira remote link and comment
+type pullRequestSummary struct {
+    Number int
+    Title  string
+    URL    string
+    Author string
+    Branch string
+    Status string
+}
+
+func linkPullRequestEnabled() bool {
+    return strings.ToLower(os.Getenv(envLinkPullRequest)) == "true"
+}
+
+func pullRequestCommentEnabled() bool {
+    return strings.ToLower(os.Getenv(envPullRequestComment)) == "true"
+}
+
+func summarizePullRequest(ctx context.Context, gh github.GitHub) (pullRequestSummary, error) {
+    pullRequest, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return pullRequestSummary{}, err
+    }
+
+    status := "Open"
+    switch {
+    case pullRequest.GetMerged():
+        status = "Merged"
+    case pullRequest.GetState() == "closed":
+        status = "Closed"
+    case pullRequest.GetDraft():
+        status = "Draft"
+    }
+
+    return pullRequestSummary{
+        Number: pullRequest.GetNumber(),
+        Title:  pullRequest.GetTitle(),
+        URL:    pullRequest.GetHTMLURL(),
+        Author: pullRequest.GetUser().GetLogin(),
+        Branch: pullRequest.GetHead().GetRef(),
+        Status: status,
+    }, nil
+}
+
+// remoteLink builds the Jira remote link for the pull request. Jira updates the existing link with the same global ID instead of adding
+// another one, which keeps repeated runs idempotent.
+func remoteLink(summary pullRequestSummary) *models.RemoteLinkScheme {
+    return &models.RemoteLinkScheme{
+        GlobalID:     summary.URL,
+        Relationship: "pull request",
+        Application: &models.RemoteLinkApplicationScheme{
+            Type: "com.github",
+            Name: "GitHub",
+        },
+        Object: &models.RemoteLinkObjectScheme{
+            URL:     summary.URL,
+            Title:   fmt.Sprintf("PR #%d: %s", summary.Number, summary.Title),
+            Summary: fmt.Sprintf("%s by %s from %s", summary.Status, summary.Author, summary.Branch),
+            Icon: &models.RemoteLinkObjectLinkScheme{
+                URL16X16: "https://github.com/favicon.ico",
+                Title:    "GitHub",
+            },
+            Status: &models.RemoteLinkObjectStatusScheme{
+                Resolved: summary.Status == "Merged" || summary.Status == "Closed",
+            },
+        },
+    }
+}
+
+// linkPullRequest records the pull request on the Jira issue, and comments on the issue the first time the link is created. Jira
+// failures are logged; only a pull request GitHub would not return is an error.
+func linkPullRequest(ctx context.Context, gh github.GitHub, config Configuration) error {
+    if !linkPullRequestEnabled() {
+        return nil
+    }
+
+    summary, err := summarizePullRequest(ctx, gh)
+    if err != nil {
+        return err
+    }
+
+    if summary.URL == "" {
+        logger.Error("Unable to link the pull request in Jira: the pull request URL is unknown")
+        return nil
+    }
+
+    client, err := config.jiraClient()
+    if err != nil {
+        logger.Errorf("Failed to create Jira client: %v", err)
+        return nil
+    }
+
+    created, err := client.linkPullRequest(config.IssueKey, remoteLink(summary))
+    if err != nil {
+        logger.Errorf("Failed to link pull request to Jira issue %s: %v", config.IssueKey, err)
+        return nil
+    }
+
+    if !created {
+        logger.Infof("Updated pull request link on Jira issue %s", config.IssueKey)
+        return nil
+    }
+
+    logger.Infof("Linked pull request to Jira issue %s", config.IssueKey)
+
+    if pullRequestCommentEnabled() {
+        if err := client.addPullRequestComment(config.IssueKey, summary); err != nil {
+            logger.Errorf("Failed to comment on Jira issue %s: %v", config.IssueKey, err)
+        }
+    }
+
+    return nil
+}
+
+// adfPullRequestComment renders the pull request summary as an Atlassian Document Format comment for Jira Cloud
+func adfPullRequestComment(summary pullRequestSummary) *models.CommentNodeScheme {
+    text := func(value string, marks ...*models.MarkScheme) *models.CommentNodeScheme {
+        return &models.CommentNodeScheme{Type: "text", Text: value, Marks: marks}
+    }
+
+    item := func(label string, value *models.CommentNodeScheme) *models.CommentNodeScheme {
+        return &models.CommentNodeScheme{
+            Type: "listItem",
+            Content: []*models.CommentNodeScheme{{
+                Type:    "paragraph",
+                Content: []*models.CommentNodeScheme{text(label+": ", &models.MarkScheme{Type: "strong"}), value},
+            }},
+        }
+    }
+
+    link := &models.MarkScheme{Type: "link", Attrs: map[string]interface{}{"href": summary.URL}}
+
+    return &models.CommentNodeScheme{
+        Version: 1,
+        Type:    "doc",
+        Content: []*models.CommentNodeScheme{
+            {
+                Type: "paragraph",
+                Content: []*models.CommentNodeScheme{
+                    text("Pull request "),
+                    text(fmt.Sprintf("#%d %s", summary.Number, summary.Title), link),
+                    text(" was opened for this issue."),
+                },
+            },
+            {
+                Type: "bulletList",
+                Content: []*models.CommentNodeScheme{
+                    item("Author", text(summary.Author)),
+                    item("Branch", text(summary.Branch, &models.MarkScheme{Type: "code"})),
+                    item("Status", text(summary.Status)),
+                },
+            },
+        },
+    }
+}
+
+// wikiPullRequestComment renders the pull request summary as wiki markup for Jira Server and Data Center
+func wikiPullRequestComment(summary pullRequestSummary) string {
+    return fmt.Sprintf("Pull request [#%d %s|%s] was opened for this issue.\n", summary.Number, summary.Title, summary.URL) +
+        fmt.Sprintf("* *Author:* %s\n", summary.Author) +
+        fmt.Sprintf("* *Branch:* {{%s}}\n", summary.Branch) +
+        fmt.Sprintf("* *Status:* %s", summary.Status)
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestLinkPullRequest(t *testing.T) {
+    var links []*models.RemoteLinkScheme
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/rest/api/3/issue/PROJ-12/remotelink" {
+            http.NotFound(w, r)
+            return
+        }
+
+        switch r.Method {
+        case http.MethodGet:
+            json.NewEncoder(w).Encode(links)
+        case http.MethodPost:
+            var link models.RemoteLinkScheme
+            json.NewDecoder(r.Body).Decode(&link)
+            if !hasRemoteLink(links, link.GlobalID) {
+                links = append(links, &link)
+            }
+
+            w.WriteHeader(http.StatusCreated)
+            w.Write([]byte(`{"id":10000,"self":"https://jira.example.com/rest/api/3/issue/PROJ-12/remotelink/10000"}`))
+        }
+    }))
+    defer server.Close()
+
+    client, err := createJiraClient(Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", DeploymentType: deploymentCloud})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    link := remoteLink(pullRequestSummary{
+        Number: 42,
+        Title:  "[PROJ-12] Add Export",
+        URL:    "https://github.com/example/repo/pull/42",
+        Author: "octocat",
+        Branch: "feature/PROJ-12-add-export",
+        Status: "Open",
+    })
+
+    for i, expected := range []bool{true, false} {
+        created, err := client.linkPullRequest("PROJ-12", link)
+        if err != nil {
+            t.Fatalf("linkPullRequest() call %d error = %v", i+1, err)
+        }
+
+        if created != expected {
+            t.Errorf("linkPullRequest() call %d created = %v, want %v", i+1, created, expected)
+        }
+    }
+
+    if len(links) != 1 {
+        t.Errorf("expected a single remote link, got %d", len(links))
+    }
+
+    if links[0].Object.Title != "PR #42: [PROJ-12] Add Export" {
+        t.Errorf("unexpected remote link title %q", links[0].Object.Title)
+    }
+}
+
+func TestWikiPullRequestComment(t *testing.T) {
+    comment := wikiPullRequestComment(pullRequestSummary{
+        Number: 42,
+        Title:  "Add Export",
+        URL:    "https://github.com/example/repo/pull/42",
+        Author: "octocat",
+        Branch: "feature/PROJ-12-add-export",
+        Status: "Merged",
+    })
+
+    expected := "Pull request [#42 Add Export|https://github.com/example/repo/pull/42] was opened for this issue.\n" +
+        "* *Author:* octocat\n" +
+        "* *Branch:* {{feature/PROJ-12-add-export}}\n" +
+        "* *Status:* Merged"
+
+    if comment != expected {
+        t.Errorf("wikiPullRequestComment() = %q, want %q", comment, expected)
+    }
+}
@@ -0,0 +1,181 @@
+package jira
+
+import (
+    "context"
+    "fmt"
+    "regexp"
+    "slices"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/branch"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+var regexIssueKey = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// maxRelatedIssues bounds the related issues fetched for one pull request, so a long-lived PR does not fetch every key it ever mentioned
+const maxRelatedIssues = 10
+
+// relatedIssue is a row of the linked issue table in the synced description
+type relatedIssue struct {
+    Key       string
+    Summary   string
+    IssueType string
+    Status    string
+
+    // InTitle marks keys named in the branch, PR title, or PR body; keys only found in commit messages are listed in the table but kept
+    // out of the title
+    InTitle bool
+}
+
+// relatedIssues fetches the other Jira issues the branch or pull request refers to. Keys Jira does not recognise are skipped, since
+// text such as UTF-8 looks like an issue key.
+func relatedIssues(ctx context.Context, gh github.GitHub, config Configuration, parentKey string) ([]relatedIssue, error) {
+    if !drivers.MultipleIssuesEnabled() {
+        return nil, nil
+    }
+
+    titleKeys, commitKeys, err := collectRelatedKeys(ctx, gh, config.IssueKey)
+    if err != nil {
+        return nil, err
+    }
+
+    keys := inAllowedProjects(append(titleKeys, commitKeys...))
+    if len(keys) == 0 {
+        return nil, nil
+    }
+
+    if len(keys) > maxRelatedIssues {
+        logger.Infof("Only the first %d of %d related Jira issues are fetched", maxRelatedIssues, len(keys))
+        keys = keys[:maxRelatedIssues]
+    }
+
+    client, err := config.jiraClient()
+    if err != nil {
+        logger.Errorf("Failed to create Jira client: %v", err)
+        return nil, nil
+    }
+
+    issues := fetchRelatedIssues(client, keys, parentKey)
+    for i := range issues {
+        issues[i].InTitle = slices.Contains(titleKeys, issues[i].Key)
+    }
+
+    return issues, nil
+}
+
+// inAllowedProjects leaves out keys from projects outside OPT_JIRA_ALLOWED_PROJECTS, when it is set, so text such as SHA-256 is not
+// looked up in Jira
+func inAllowedProjects(keys []string) []string {
+    allowedProjects := loadGateRules().AllowedProjects
+    if len(allowedProjects) == 0 {
+        return keys
+    }
+
+    var allowed []string
+    for _, key := range keys {
+        if project, _, _ := strings.Cut(key, "-"); containsFold(allowedProjects, project) {
+            allowed = append(allowed, key)
+        }
+    }
+
+    return allowed
+}
+
+func fetchRelatedIssues(client issueClient, keys []string, parentKey string) []relatedIssue {
+    var issues []relatedIssue
+    for _, key := range keys {
+        // The parent already appears in the title prefix, so it is not listed as a related issue
+        if key == parentKey {
+            continue
+        }
+
+        jiraIssue, err := client.getIssue(key)
+        if err != nil {
+            logger.Infof("Skipping related Jira issue %s: %v", key, err)
+            continue
+        }
+
+        issues = append(issues, relatedIssue{
+            Key:       key,
+            Summary:   jiraIssue.Summary,
+            IssueType: jiraIssue.IssueType,
+            Status:    jiraIssue.Status,
+        })
+    }
+
+    return issues
+}
+
+// collectRelatedKeys gathers issue keys other than the primary one. Keys from the branch name, PR title, and PR body are returned apart
+// from keys only found in commit messages.
+func collectRelatedKeys(ctx context.Context, gh github.GitHub, primaryKey string) ([]string, []string, error) {
+    var sources []string
+    if branchName, err := gh.GetBranchName(ctx); err == nil {
+        if match, ok := branch.Parse(branchName); ok {
+            keys, _ := branch.RelatedKeys(match.Name)
+            sources = append(sources, strings.Join(keys, " "))
+        }
+    }
+
+    pullRequest, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    body := pullRequest.GetBody()
+
+    // A title written by an earlier sync only repeats keys found then, so it would keep keys that have since been removed
+    if lastTitle, ok := github.LastSyncedTitle(body); !ok || lastTitle != pullRequest.GetTitle() {
+        sources = append(sources, pullRequest.GetTitle())
+    }
+
+    sources = append(sources, github.StripSyncedContent(body))
+
+    // Commit messages only add keys, so the title and body are still searched when they cannot be read
+    messages, err := gh.GetPRCommitMessages(ctx)
+    if err != nil {
+        logger.Errorf("Failed to read commit messages for Jira issue keys: %v", err)
+    }
+
+    titleKeys := extractIssueKeys(sources, primaryKey)
+    return titleKeys, extractIssueKeys(messages, append([]string{primaryKey}, titleKeys...)...), nil
+}
+
+// extractIssueKeys returns the issue keys in the order they first appear, leaving out skipKeys
+func extractIssueKeys(sources []string, skipKeys ...string) []string {
+    seen := make(map[string]bool)
+    for _, key := range skipKeys {
+        seen[key] = true
+    }
+
+    var keys []string
+    for _, source := range sources {
+        for _, key := range regexIssueKey.FindAllString(source, -1) {
+            if !seen[key] {
+                seen[key] = true
+                keys = append(keys, key)
+            }
+        }
+    }
+
+    return keys
+}
+
+// linkedIssuesTable renders the issues as a Markdown table whose keys link back to Jira
+func linkedIssuesTable(baseURL string, issues []relatedIssue) string {
+    cell := func(value string) string {
+        return strings.ReplaceAll(markdownEscaper.Replace(value), "|", `\|`)
+    }
+
+    lines := []string{"### Linked Issues", "", "| Issue | Summary | Type | Status |", "|" + strings.Repeat(" --- |", 4)}
+    for _, issue := range issues {
+        link := fmt.Sprintf("[%s](%s/browse/%s)", issue.Key, strings.TrimSuffix(baseURL, "/"), issue.Key)
+        lines = append(lines, tableRow([]string{link, cell(issue.Summary), cell(issue.IssueType), cell(issue.Status)}, 4))
+    }
+
+    return strings.Join(lines, "\n")
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func TestExtractIssueKeys(t *testing.T) {
+    sources := []string{
+        "PROJ-15",
+        "[PROJ-12] Shared fix",
+        "Also resolves PROJ-20 and OPS-3.\n\nSee PROJ-15.",
+        "PROJ-21: fix the shared helper\n\nproj-99 is not a key",
+    }
+
+    expected := []string{"PROJ-15", "PROJ-20", "OPS-3", "PROJ-21"}
+    if actual := extractIssueKeys(sources, "PROJ-12"); strings.Join(actual, ",") != strings.Join(expected, ",") {
+        t.Errorf("extractIssueKeys() = %v, want %v", actual, expected)
+    }
+}
+
+func TestInAllowedProjects(t *testing.T) {
+    keys := []string{"PROJ-15", "SHA-256", "OPS-3", "UTF-8"}
+
+    t.Setenv(envAllowedProjects, "")
+    if actual := inAllowedProjects(keys); strings.Join(actual, ",") != strings.Join(keys, ",") {
+        t.Errorf("inAllowedProjects() without allowed projects = %v, want %v", actual, keys)
+    }
+
+    t.Setenv(envAllowedProjects, "PROJ, OPS")
+    if actual := inAllowedProjects(keys); strings.Join(actual, ",") != "PROJ-15,OPS-3" {
+        t.Errorf("inAllowedProjects() = %v, want [PROJ-15 OPS-3]", actual)
+    }
+}
+
+func TestFetchRelatedIssues(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/rest/api/3/issue/PROJ-15":
+            w.Write([]byte(`{"key":"PROJ-15","fields":{"summary":"Fix shared helper","issuetype":{"name":"Bug"},"status":{"name":"In Review"}}}`))
+        case "/rest/api/3/issue/PROJ-1":
+            t.Error("the parent issue should not be fetched as a related issue")
+            http.NotFound(w, r)
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    defer server.Close()
+
+    client, err := createJiraClient(Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", DeploymentType: deploymentCloud})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    issues := fetchRelatedIssues(client, []string{"PROJ-1", "PROJ-15", "UTF-8"}, "PROJ-1")
+
+    expected := relatedIssue{Key: "PROJ-15", Summary: "Fix shared helper", IssueType: "Bug", Status: "In Review"}
+    if len(issues) != 1 || issues[0] != expected {
+        t.Errorf("fetchRelatedIssues() = %+v, want [%+v]", issues, expected)
+    }
+}
+
+func TestLinkedIssuesTable(t *testing.T) {
+    table := linkedIssuesTable("https://example.atlassian.net/", []relatedIssue{
+        {Key: "PROJ-12", Summary: "Add export", IssueType: "Story", Status: "In Progress"},
+        {Key: "PROJ-15", Summary: "Fix a|b parsing", IssueType: "Bug", Status: "In Review"},
+    })
+
+    expected := "### Linked Issues\n\n" +
+        "| Issue | Summary | Type | Status |\n" +
+        "| --- | --- | --- | --- |\n" +
+        "| [PROJ-12](https://example.atlassian.net/browse/PROJ-12) | Add export | Story | In Progress |\n" +
+        "| [PROJ-15](https://example.atlassian.net/browse/PROJ-15) | Fix a\\|b parsing | Bug | In Review |"
+
+    if table != expected {
+        t.Errorf("linkedIssuesTable() = %q, want %q", table, expected)
+    }
+}
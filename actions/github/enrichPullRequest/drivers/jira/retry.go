@@ -0,0 +1,36 @@
+package jira
+
+import (
+    "fmt"
+    "os"
+    "strings"
+    "time"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/retry"
+)
+
+const envTimeout = "OPT_JIRA_TIMEOUT"
+
+// defaultTimeout bounds every Jira request in a run, including the time spent waiting to retry
+const defaultTimeout = 2 * time.Minute
+
+// newRetryTransport retries requests Jira rejected for rate limiting, and idempotent requests that failed with a gateway or
+// availability error
+func newRetryTransport() *retry.Transport {
+    return retry.New("Jira")
+}
+
+// jiraTimeout reads the overall Jira timeout, such as "90s" or "2m"
+func jiraTimeout() (time.Duration, error) {
+    value := strings.TrimSpace(os.Getenv(envTimeout))
+    if value == "" {
+        return defaultTimeout, nil
+    }
+
+    timeout, err := time.ParseDuration(value)
+    if err != nil || timeout <= 0 {
+        return 0, fmt.Errorf("invalid %s %q; expected a duration such as \"90s\" or \"2m\"", envTimeout, value)
+    }
+
+    return timeout, nil
+}
@@ -0,0 +1,71 @@
+package jira
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestJiraTimeout(t *testing.T) {
+    tests := []struct {
+        value    string
+        expected time.Duration
+        wantErr  bool
+    }{
+        {value: "", expected: defaultTimeout},
+        {value: "90s", expected: 90 * time.Second},
+        {value: "soon", wantErr: true},
+        {value: "-1m", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.value, func(t *testing.T) {
+            t.Setenv(envTimeout, tt.value)
+
+            timeout, err := jiraTimeout()
+            if (err != nil) != tt.wantErr || timeout != tt.expected {
+                t.Errorf("jiraTimeout() = %v, %v, want %v, wantErr %v", timeout, err, tt.expected, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestGetIssueRequestsOnce(t *testing.T) {
+    t.Setenv(envTransitions, "opened:In Review")
+
+    var queries []string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        queries = append(queries, r.URL.RawQuery)
+        w.Write([]byte(`{"key":"PROJ-12","transitions":[{"id":"21","name":"Start Review","to":{"name":"In Review"}}],"fields":{"summary":"Add export"}}`))
+    }))
+    defer server.Close()
+
+    client, err := createJiraClient(Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", DeploymentType: deploymentCloud, CustomFields: parseCustomFields("customfield_10034:Acceptance Criteria")})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    config := Configuration{IssueKey: "PROJ-12", client: &cachingClient{issueClient: client, issues: make(map[string]*issue)}}
+    for i := 0; i < 2; i++ {
+        jiraIssue, err := config.client.getIssue("PROJ-12")
+        if err != nil {
+            t.Fatalf("getIssue() error = %v", err)
+        }
+
+        if len(jiraIssue.Transitions) != 1 {
+            t.Errorf("getIssue() transitions = %v, want the expanded transitions", jiraIssue.Transitions)
+        }
+    }
+
+    if len(queries) != 1 {
+        t.Fatalf("expected one Jira request, got %d", len(queries))
+    }
+
+    for _, expected := range []string{"expand=transitions", "fields=summary%2Cdescription", "customfield_10020", "customfield_10034"} {
+        if !strings.Contains(queries[0], expected) {
+            t.Errorf("query %q does not contain %q", queries[0], expected)
+        }
+    }
+}
@@ -0,0 +1,151 @@
+package jira
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+    "github.com/ctreminiom/go-atlassian/pkg/infra/models"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const envTransitions = "OPT_JIRA_TRANSITIONS"
+
+func transitionsConfigured() bool {
+    return os.Getenv(envTransitions) != ""
+}
+
+// parseTransitions reads event to transition name pairs such as "opened:In Review,merged:Done"
+func parseTransitions(value string) map[string]string {
+    transitions := make(map[string]string)
+    for _, pair := range strings.Split(value, ",") {
+        event, transition, ok := strings.Cut(pair, ":")
+        if !ok {
+            continue
+        }
+
+        event = strings.ToLower(strings.TrimSpace(event))
+        transition = strings.TrimSpace(transition)
+        if event != "" && transition != "" {
+            transitions[event] = transition
+        }
+    }
+
+    return transitions
+}
+
+// pullRequestEvent returns the pull_request action that triggered the run, reporting a merged PR as "merged" rather than "closed"
+func pullRequestEvent(gh github.GitHub) (string, error) {
+    action, merged := gh.PullRequestAction()
+    if action == "" {
+        return "", errors.New("the event that triggered the run does not name an action")
+    }
+
+    if action == "closed" && merged {
+        return "merged", nil
+    }
+
+    return action, nil
+}
+
+// findTransition matches the configured name against the transition name or the status it leads to
+func findTransition(transitions []*models.IssueTransitionScheme, name string) *models.IssueTransitionScheme {
+    for _, transition := range transitions {
+        if strings.EqualFold(transition.Name, name) {
+            return transition
+        }
+    }
+
+    for _, transition := range transitions {
+        if transition.To != nil && strings.EqualFold(transition.To.Name, name) {
+            return transition
+        }
+    }
+
+    return nil
+}
+
+func transitionNames(transitions []*models.IssueTransitionScheme) string {
+    var names []string
+    for _, transition := range transitions {
+        names = append(names, transition.Name)
+    }
+
+    if len(names) == 0 {
+        return "none"
+    }
+
+    return strings.Join(names, ", ")
+}
+
+// transitionIssue moves the Jira issue through the transition configured for the current PR event
+// A transition Jira refuses is explained in a PR comment rather than failing the run; only a comment that cannot be posted is returned.
+func transitionIssue(ctx context.Context, gh github.GitHub, config Configuration) error {
+    if !transitionsConfigured() {
+        return nil
+    }
+
+    event, err := pullRequestEvent(gh)
+    if err != nil {
+        logger.Errorf("Unable to determine the pull request event for Jira transitions: %v", err)
+        return nil
+    }
+
+    transitionName, ok := parseTransitions(os.Getenv(envTransitions))[event]
+    if !ok {
+        logger.Infof("No Jira transition configured for pull request event '%s'", event)
+        return nil
+    }
+
+    if err := moveIssue(config, transitionName); err != nil {
+        logger.Errorf("Failed to transition Jira issue %s: %v", config.IssueKey, err)
+
+        comment := "**Jira Transition Failed**\n\n" +
+            fmt.Sprintf("Unable to apply the `%s` transition to Jira issue `%s` for the `%s` event.\n\n", transitionName, config.IssueKey, event) +
+            fmt.Sprintf("**Error:** %v\n\n", err) +
+            "Please check that the transition is available from the issue's current status and that the Jira user is allowed to perform it."
+
+        return gh.AddPRComment(ctx, comment)
+    }
+
+    return nil
+}
+
+func moveIssue(config Configuration, transitionName string) error {
+    client, err := config.jiraClient()
+    if err != nil {
+        return fmt.Errorf("failed to create Jira client: %v", err)
+    }
+
+    // The issue is usually already fetched with its transitions expanded, which saves asking Jira for them again
+    current, currentErr := client.getIssue(config.IssueKey)
+
+    var transitions []*models.IssueTransitionScheme
+    if currentErr == nil && current.Transitions != nil {
+        transitions = current.Transitions
+    } else if transitions, err = client.getTransitions(config.IssueKey); err != nil {
+        return err
+    }
+
+    transition := findTransition(transitions, transitionName)
+    if transition == nil {
+        // Workflows rarely offer a transition into the current status, so an issue already there is not a failure
+        if currentErr == nil && strings.EqualFold(current.Status, transitionName) {
+            logger.Infof("Jira issue %s is already in status '%s'", config.IssueKey, current.Status)
+            return nil
+        }
+
+        return fmt.Errorf("no transition named %q is available; available transitions: %s", transitionName, transitionNames(transitions))
+    }
+
+    if err := client.moveIssue(config.IssueKey, transition.ID); err != nil {
+        return err
+    }
+
+    logger.Infof("Moved Jira issue %s through transition '%s'", config.IssueKey, transition.Name)
+    return nil
+}
@@ -0,0 +1,106 @@
+package jira
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+)
+
+func TestParseTransitions(t *testing.T) {
+    actual := parseTransitions("opened:In Review, Merged : Done,converted_to_draft:In Progress,invalid,closed:")
+    expected := map[string]string{
+        "opened":             "In Review",
+        "merged":             "Done",
+        "converted_to_draft": "In Progress",
+    }
+
+    if !reflect.DeepEqual(actual, expected) {
+        t.Errorf("parseTransitions() = %v, want %v", actual, expected)
+    }
+}
+
+func TestPullRequestEvent(t *testing.T) {
+    tests := []struct {
+        name     string
+        action   string
+        merged   bool
+        expected string
+    }{
+        {name: "opened", action: "opened", expected: "opened"},
+        {name: "closed without merging", action: "closed", expected: "closed"},
+        {name: "merged", action: "closed", merged: true, expected: "merged"},
+        {name: "converted to draft", action: "converted_to_draft", expected: "converted_to_draft"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            event, err := pullRequestEvent(&fakeGitHub{action: tt.action, merged: tt.merged})
+            if err != nil {
+                t.Fatalf("pullRequestEvent() error = %v", err)
+            }
+
+            if event != tt.expected {
+                t.Errorf("pullRequestEvent() = %q, want %q", event, tt.expected)
+            }
+        })
+    }
+
+    if _, err := pullRequestEvent(&fakeGitHub{}); err == nil {
+        t.Error("pullRequestEvent() without an action should return an error")
+    }
+}
+
+func TestMoveIssue(t *testing.T) {
+    var movedTo string
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/PROJ-12/transitions":
+            w.Write([]byte(`{"transitions":[{"id":"21","name":"Start Review","to":{"name":"In Review"}},{"id":"31","name":"Done","to":{"name":"Done"}}]}`))
+        case r.Method == http.MethodPost && r.URL.Path == "/rest/api/3/issue/PROJ-12/transitions":
+            var body struct {
+                Transition struct {
+                    ID string `json:"id"`
+                } `json:"transition"`
+            }
+            json.NewDecoder(r.Body).Decode(&body)
+            movedTo = body.Transition.ID
+            w.WriteHeader(http.StatusNoContent)
+        case r.Method == http.MethodGet && r.URL.Path == "/rest/api/3/issue/PROJ-12":
+            w.Write([]byte(`{"key":"PROJ-12","fields":{"summary":"Add export","status":{"name":"In Progress"}}}`))
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    defer server.Close()
+
+    config := Configuration{URL: server.URL, Email: "dev@example.com", Token: "token", IssueKey: "PROJ-12", DeploymentType: deploymentCloud}
+
+    tests := []struct {
+        name       string
+        transition string
+        expectedID string
+        wantErr    bool
+    }{
+        {name: "by transition name", transition: "start review", expectedID: "21"},
+        {name: "by target status", transition: "In Review", expectedID: "21"},
+        {name: "already in status", transition: "In Progress", expectedID: ""},
+        {name: "unavailable transition", transition: "Blocked", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            movedTo = ""
+
+            err := moveIssue(config, tt.transition)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("moveIssue() error = %v, wantErr %v", err, tt.wantErr)
+            }
+
+            if movedTo != tt.expectedID {
+                t.Errorf("moveIssue() used transition %q, want %q", movedTo, tt.expectedID)
+            }
+        })
+    }
+}
@@ -0,0 +1,326 @@
+package jira
+
+import (
+    "fmt"
+    "regexp"
+    "strings"
+)
+
+// wikiPanelAlerts maps Jira wiki panel macros to GitHub alert types
+var wikiPanelAlerts = map[string]string{
+    "info":    "NOTE",
+    "tip":     "TIP",
+    "note":    "IMPORTANT",
+    "warning": "WARNING",
+}
+
+var (
+    regexWikiHeading   = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+    regexWikiQuote     = regexp.MustCompile(`^bq\.\s+(.*)$`)
+    regexWikiList      = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+    regexWikiBlock     = regexp.MustCompile(`^\{(code|noformat|panel|info|tip|note|warning|quote)(?::([^}]*))?\}(.*)$`)
+    regexWikiMonospace = regexp.MustCompile(`\{\{(.+?)\}\}`)
+    regexWikiColor     = regexp.MustCompile(`\{color(?::[^}]*)?\}`)
+    regexWikiImage     = regexp.MustCompile(`!([^!\s|]+)(?:\|[^!]*)?!`)
+    regexWikiMention   = regexp.MustCompile(`\[~(?:accountid:)?([^\]]+)\]`)
+    regexWikiLink      = regexp.MustCompile(`\[([^\]|]*)\|([^\]|]+)(?:\|[^\]]*)?\]`)
+    regexWikiURL       = regexp.MustCompile(`\[((?:https?|mailto):[^\]]+)\]`)
+)
+
+// WikiToMarkdown renders Jira Server/Data Center wiki markup as GitHub Markdown
+func WikiToMarkdown(text string) string {
+    lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+    return strings.TrimSpace(strings.Join(renderWikiLines(lines), "\n"))
+}
+
+func renderWikiLines(lines []string) []string {
+    var result []string
+    for i := 0; i < len(lines); i++ {
+        line := strings.TrimRight(lines[i], " \t")
+        trimmed := strings.TrimSpace(line)
+
+        if matches := regexWikiBlock.FindStringSubmatch(trimmed); matches != nil {
+            body, next := collectWikiBlock(lines, i, matches[1], matches[3])
+            result = append(result, renderWikiBlock(matches[1], matches[2], body)...)
+            i = next
+            continue
+        }
+
+        if strings.HasPrefix(trimmed, "|") {
+            start := i
+            for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "|") {
+                i++
+            }
+
+            // GitHub tables only start after a blank line
+            if len(result) > 0 && result[len(result)-1] != "" {
+                result = append(result, "")
+            }
+
+            result = append(result, renderWikiTable(lines[start:i+1])...)
+            continue
+        }
+
+        switch matches := regexWikiHeading.FindStringSubmatch(trimmed); {
+        case matches != nil:
+            result = append(result, strings.Repeat("#", int(matches[1][0]-'0'))+" "+renderWikiInline(matches[2]))
+        case regexWikiQuote.MatchString(trimmed):
+            result = append(result, "> "+renderWikiInline(regexWikiQuote.FindStringSubmatch(trimmed)[1]))
+        case trimmed == "----":
+            result = append(result, "---")
+        case regexWikiList.MatchString(trimmed):
+            result = append(result, renderWikiListItem(regexWikiList.FindStringSubmatch(trimmed)))
+        default:
+            result = append(result, renderWikiInline(line))
+        }
+    }
+
+    return result
+}
+
+// collectWikiBlock returns the lines between a block macro and its closing tag, and the index of the closing line
+func collectWikiBlock(lines []string, start int, macro string, rest string) ([]string, int) {
+    closing := "{" + macro + "}"
+
+    if index := strings.Index(rest, closing); index != -1 {
+        return []string{rest[:index]}, start
+    }
+
+    var body []string
+    if rest != "" {
+        body = append(body, rest)
+    }
+
+    for i := start + 1; i < len(lines); i++ {
+        if index := strings.Index(lines[i], closing); index != -1 {
+            if before := lines[i][:index]; strings.TrimSpace(before) != "" {
+                body = append(body, before)
+            }
+
+            return body, i
+        }
+
+        body = append(body, lines[i])
+    }
+
+    // An unclosed macro runs to the end of the text, as it does in Jira
+    return body, len(lines) - 1
+}
+
+func renderWikiBlock(macro string, parameters string, body []string) []string {
+    switch macro {
+    case "code", "noformat":
+        return append(append([]string{"```" + wikiCodeLanguage(macro, parameters)}, body...), "```")
+    case "quote":
+        return prefixWikiLines(renderWikiLines(body), "")
+    case "panel":
+        var header string
+        if title := wikiParameter(parameters, "title"); title != "" {
+            header = "**" + renderWikiInline(title) + "**"
+        }
+
+        return prefixWikiLines(renderWikiLines(body), header)
+    default:
+        header := "[!" + wikiPanelAlerts[macro] + "]"
+        if title := wikiParameter(parameters, "title"); title != "" {
+            return prefixWikiLines(append([]string{"**" + renderWikiInline(title) + "**", ""}, renderWikiLines(body)...), header)
+        }
+
+        return prefixWikiLines(renderWikiLines(body), header)
+    }
+}
+
+func prefixWikiLines(lines []string, header string) []string {
+    var result []string
+    if header != "" {
+        result = append(result, "> "+header)
+    }
+
+    for _, line := range lines {
+        if line == "" {
+            result = append(result, ">")
+        } else {
+            result = append(result, "> "+line)
+        }
+    }
+
+    return result
+}
+
+// wikiCodeLanguage reads the language from {code:java} or {code:title=Example|language=java}
+func wikiCodeLanguage(macro string, parameters string) string {
+    if macro != "code" || parameters == "" {
+        return ""
+    }
+
+    if language := wikiParameter(parameters, "language"); language != "" {
+        return language
+    }
+
+    if first := strings.Split(parameters, "|")[0]; !strings.Contains(first, "=") {
+        return first
+    }
+
+    return ""
+}
+
+func wikiParameter(parameters string, name string) string {
+    for _, parameter := range strings.Split(parameters, "|") {
+        if key, value, ok := strings.Cut(parameter, "="); ok && strings.TrimSpace(key) == name {
+            return strings.TrimSpace(value)
+        }
+    }
+
+    return ""
+}
+
+func renderWikiListItem(matches []string) string {
+    markers := matches[1]
+    if markers == "-" {
+        markers = "*"
+    }
+
+    // Each nesting level is indented by the width of its parent's Markdown marker
+    var indentation string
+    for _, marker := range markers[:len(markers)-1] {
+        if marker == '#' {
+            indentation += "   "
+        } else {
+            indentation += "  "
+        }
+    }
+
+    marker := "- "
+    if markers[len(markers)-1] == '#' {
+        marker = "1. "
+    }
+
+    return indentation + marker + renderWikiInline(matches[2])
+}
+
+func renderWikiTable(lines []string) []string {
+    var rows [][]string
+    columns := 0
+    for _, line := range lines {
+        line = strings.TrimSpace(renderWikiInline(line))
+
+        separator := "|"
+        if strings.HasPrefix(line, "||") {
+            separator = "||"
+        }
+
+        cells := strings.Split(strings.TrimSuffix(strings.TrimPrefix(line, separator), separator), separator)
+        for i, cell := range cells {
+            cells[i] = strings.TrimSpace(cell)
+        }
+
+        if len(cells) > columns {
+            columns = len(cells)
+        }
+
+        rows = append(rows, cells)
+    }
+
+    // GitHub tables require a header row, so the first row is used whether or not it is a wiki header row
+    result := []string{tableRow(rows[0], columns), "|" + strings.Repeat(" --- |", columns)}
+    for _, row := range rows[1:] {
+        result = append(result, tableRow(row, columns))
+    }
+
+    return result
+}
+
+func renderWikiInline(text string) string {
+    // Converted spans are swapped for placeholders so later rules cannot rewrite code or URLs
+    var placeholders []string
+    protect := func(value string) string {
+        placeholders = append(placeholders, value)
+        return fmt.Sprintf("\x00%d\x00", len(placeholders)-1)
+    }
+
+    text = regexWikiMonospace.ReplaceAllStringFunc(text, func(match string) string {
+        return protect(codeSpan(regexWikiMonospace.FindStringSubmatch(match)[1]))
+    })
+    text = regexWikiColor.ReplaceAllString(text, "")
+    text = regexWikiImage.ReplaceAllStringFunc(text, func(match string) string {
+        source := regexWikiImage.FindStringSubmatch(match)[1]
+        if strings.Contains(source, "://") {
+            return protect("![](" + source + ")")
+        }
+
+        // Jira attachments need a Jira session to view, so only a placeholder is rendered
+        return protect("*\\[Attachment: " + markdownEscaper.Replace(source) + "\\]*")
+    })
+    text = regexWikiMention.ReplaceAllStringFunc(text, func(match string) string {
+        // A code span keeps GitHub from notifying an unrelated GitHub user with the same name
+        return protect(codeSpan("@" + regexWikiMention.FindStringSubmatch(match)[1]))
+    })
+    text = regexWikiLink.ReplaceAllStringFunc(text, func(match string) string {
+        matches := regexWikiLink.FindStringSubmatch(match)
+        return protect("[" + matches[1] + "](" + matches[2] + ")")
+    })
+    text = regexWikiURL.ReplaceAllStringFunc(text, func(match string) string {
+        return protect("<" + regexWikiURL.FindStringSubmatch(match)[1] + ">")
+    })
+
+    text = replaceDelimited(text, '*', "**", "**", false)
+    text = replaceDelimited(text, '_', "*", "*", false)
+    text = replaceDelimited(text, '-', "~~", "~~", false)
+    text = replaceDelimited(text, '+', "<ins>", "</ins>", false)
+    text = replaceDelimited(text, '^', "<sup>", "</sup>", true)
+    text = replaceDelimited(text, '~', "<sub>", "</sub>", true)
+    text = strings.ReplaceAll(text, `\\`, "<br>")
+
+    for i, value := range placeholders {
+        text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), value, 1)
+    }
+
+    return text
+}
+
+// replaceDelimited converts wiki spans like *bold*. Unless intraword is set, as it is for H~2~O, the delimiters must sit at word boundaries.
+func replaceDelimited(text string, delimiter byte, open string, close string, intraword bool) string {
+    var result strings.Builder
+    for i := 0; i < len(text); i++ {
+        if text[i] == delimiter && canOpenSpan(text, i, intraword) {
+            if end := findSpanClose(text, i, intraword); end != -1 {
+                result.WriteString(open + text[i+1:end] + close)
+                i = end
+                continue
+            }
+        }
+
+        result.WriteByte(text[i])
+    }
+
+    return result.String()
+}
+
+func canOpenSpan(text string, index int, intraword bool) bool {
+    if index > 0 && ((!intraword && isWordByte(text[index-1])) || text[index-1] == text[index]) {
+        return false
+    }
+
+    return index+1 < len(text) && text[index+1] != ' ' && text[index+1] != text[index]
+}
+
+func findSpanClose(text string, start int, intraword bool) int {
+    for i := start + 2; i < len(text); i++ {
+        if text[i] != text[start] || text[i-1] == ' ' {
+            continue
+        }
+
+        if i+1 == len(text) || ((intraword || !isWordByte(text[i+1])) && text[i+1] != text[start]) {
+            return i
+        }
+    }
+
+    return -1
+}
+
+func isWordByte(character byte) bool {
+    return character >= 0x80 ||
+        (character >= 'a' && character <= 'z') ||
+        (character >= 'A' && character <= 'Z') ||
+        (character >= '0' && character <= '9')
+}
@@ -0,0 +1,80 @@
+package jira
+
+import "testing"
+
+func TestWikiToMarkdown(t *testing.T) {
+    tests := []struct {
+        name     string
+        wiki     string
+        expected string
+    }{
+        {
+            name:     "headings and rule",
+            wiki:     "h1. Overview\nh3. Details\n----",
+            expected: "# Overview\n### Details\n---",
+        },
+        {
+            name:     "text effects",
+            wiki:     "*bold* _italic_ -removed- +inserted+ H~2~O x^2^ {{code_span}}",
+            expected: "**bold** *italic* ~~removed~~ <ins>inserted</ins> H<sub>2</sub>O x<sup>2</sup> `code_span`",
+        },
+        {
+            name:     "hyphens and asterisks inside words are left alone",
+            wiki:     "a well-known snake_case value of 2*3*4",
+            expected: "a well-known snake_case value of 2*3*4",
+        },
+        {
+            name:     "links and mentions",
+            wiki:     "See [the spec|https://example.com/a_b-c] or [https://example.com] and ask [~jdoe]",
+            expected: "See [the spec](https://example.com/a_b-c) or <https://example.com> and ask `@jdoe`",
+        },
+        {
+            name:     "nested lists",
+            wiki:     "* Parse\n** Validate\n# First\n#* Detail\n- Dash",
+            expected: "- Parse\n  - Validate\n1. First\n   - Detail\n- Dash",
+        },
+        {
+            name:     "code blocks",
+            wiki:     "{code:title=Example.java|language=java}\nint x = 1;\n{code}\n{noformat}\n*raw*\n{noformat}\n{code:go}fmt.Println(){code}",
+            expected: "```java\nint x = 1;\n```\n```\n*raw*\n```\n```go\nfmt.Println()\n```",
+        },
+        {
+            name:     "quotes",
+            wiki:     "bq. Short quote\n{quote}\nFirst\n\nSecond\n{quote}",
+            expected: "> Short quote\n> First\n>\n> Second",
+        },
+        {
+            name:     "panels",
+            wiki:     "{info}Behind a flag.{info}\n\n{warning:title=Careful}\nLarge exports are slow.\n{warning}\n\n{panel:title=Notes}\nPlain panel\n{panel}",
+            expected: "> [!NOTE]\n> Behind a flag.\n\n> [!WARNING]\n> **Careful**\n>\n> Large exports are slow.\n\n> **Notes**\n> Plain panel",
+        },
+        {
+            name:     "tables",
+            wiki:     "Formats:\n||Format||Supported||\n|CSV|Yes|\n|JSON|[later|https://example.com]|",
+            expected: "Formats:\n\n| Format | Supported |\n| --- | --- |\n| CSV | Yes |\n| JSON | [later](https://example.com) |",
+        },
+        {
+            name:     "images and colors",
+            wiki:     "!mockup.png|thumbnail! !https://example.com/flow.png! {color:red}urgent{color}",
+            expected: "*\\[Attachment: mockup.png\\]* ![](https://example.com/flow.png) urgent",
+        },
+        {
+            name:     "line breaks",
+            wiki:     "first\\\\second\r\nthird",
+            expected: "first<br>second\nthird",
+        },
+        {
+            name:     "empty",
+            wiki:     "",
+            expected: "",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if actual := WikiToMarkdown(tt.wiki); actual != tt.expected {
+                t.Errorf("WikiToMarkdown() mismatch\n--- got ---\n%s\n--- want ---\n%s", actual, tt.expected)
+            }
+        })
+    }
+}
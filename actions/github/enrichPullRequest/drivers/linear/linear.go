@@ -0,0 +1,290 @@
+package linear
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net/http"
+    "os"
+    "regexp"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/branch"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const Name = "linear"
+
+const envToken = "OPT_LINEAR_TOKEN"
+const envAPIURL = "OPT_LINEAR_API_URL"
+
+const defaultAPIURL = "https://api.linear.app/graphql"
+
+// regexIssueKey matches Linear's own branch names, such as jdoe/eng-123-add-export, which put the identifier in lower case
+var regexIssueKey = regexp.MustCompile(`(?i)(?:^|/)([a-z][a-z0-9]*-[0-9]+)(?:[/-]|$)`)
+
+const issueQuery = `query Issue($id: String!) {
+  issue(id: $id) {
+    identifier
+    title
+    description
+    parent {
+      identifier
+    }
+    labels {
+      nodes {
+        name
+        color
+      }
+    }
+  }
+}`
+
+type Configuration struct {
+    APIURL   string
+    Token    string
+    IssueKey string
+}
+
+type Label struct {
+    Name  string `json:"name"`
+    Color string `json:"color"`
+}
+
+type Information struct {
+    ParentPrefix string
+    Title        string
+    Description  string
+    Labels       []Label
+}
+
+type graphQLRequest struct {
+    Query     string                 `json:"query"`
+    Variables map[string]interface{} `json:"variables"`
+}
+
+type graphQLError struct {
+    Message    string `json:"message"`
+    Extensions struct {
+        Code string `json:"code"`
+    } `json:"extensions"`
+}
+
+type issueResponse struct {
+    // Data is null when the whole query failed, and holds a null issue when the query ran but the issue does not exist
+    Data *struct {
+        Issue *struct {
+            Identifier  string `json:"identifier"`
+            Title       string `json:"title"`
+            Description string `json:"description"`
+            Parent      *struct {
+                Identifier string `json:"identifier"`
+            } `json:"parent"`
+            Labels struct {
+                Nodes []Label `json:"nodes"`
+            } `json:"labels"`
+        } `json:"issue"`
+    } `json:"data"`
+    Errors []graphQLError `json:"errors"`
+}
+
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    if os.Getenv(envToken) == "" {
+        return fmt.Errorf("%s must be set when configured strategy is '%s'", envToken, Name)
+    }
+
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    options := syncOptions()
+    if synced, err := options.AlreadySynced(ctx, gh); err != nil || synced {
+        return err
+    }
+
+    branchName, err := gh.GetBranchName(ctx)
+    if err != nil {
+        return err
+    }
+
+    issueKey := GetIssueKeyFromBranchName(branchName)
+    if issueKey == "" {
+        return fmt.Errorf("%w: branch %s does not contain an issue key", drivers.ErrNotFound, branchName)
+    }
+
+    config := Configuration{
+        APIURL:   apiURL(),
+        Token:    os.Getenv(envToken),
+        IssueKey: issueKey,
+    }
+
+    linear, err := getLinearInfo(ctx, config)
+    if err != nil {
+        return drivers.FetchFailed(ctx, gh, drivers.Tracker{
+            Name: "Linear",
+            Item: "issue",
+            Solutions: []string{
+                "Check that the `" + envToken + "` environment variable is set correctly",
+                "Ensure the Linear user has access to the team that owns the issue: `" + issueKey + "`",
+            },
+        }, issueKey, err)
+    }
+
+    err = drivers.Sync(ctx, gh, drivers.Issue{
+        Key:          issueKey,
+        Title:        linear.Title,
+        Description:  linear.Description,
+        ParentPrefix: linear.ParentPrefix,
+    }, options)
+    if err != nil {
+        return err
+    }
+
+    var labelErrors []error
+    if issueLabelSyncEnabled() {
+        for _, label := range linear.Labels {
+            labelErrors = append(labelErrors, drivers.ApplyLabel(ctx, gh, label.Name, "Synced from Linear", strings.TrimPrefix(label.Color, "#")))
+        }
+    }
+
+    return errors.Join(labelErrors...)
+}
+
+// GetIssueKeyFromBranchName returns the upper-cased Linear identifier in the branch name, or "" if there is none. The branch-name
+// patterns are tried first so OPT_BRANCH_PATTERNS still applies.
+func GetIssueKeyFromBranchName(branchName string) string {
+    if match, ok := branch.Parse(branchName); ok {
+        return match.Key
+    }
+
+    matches := regexIssueKey.FindStringSubmatch(branchName)
+    if matches == nil {
+        return ""
+    }
+
+    return strings.ToUpper(matches[1])
+}
+
+func getIssue(ctx context.Context, config Configuration) (*issueResponse, error) {
+    body, err := json.Marshal(graphQLRequest{
+        Query:     issueQuery,
+        Variables: map[string]interface{}{"id": config.IssueKey},
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    request, err := http.NewRequestWithContext(ctx, http.MethodPost, config.APIURL, bytes.NewReader(body))
+    if err != nil {
+        return nil, err
+    }
+
+    request.Header.Set("Content-Type", "application/json")
+    request.Header.Set("Authorization", config.Token)
+
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        return nil, fmt.Errorf("failed to fetch Linear issue %s: %v", config.IssueKey, err)
+    }
+    defer response.Body.Close()
+
+    if response.StatusCode == http.StatusUnauthorized {
+        return nil, &drivers.TrackerError{
+            IsAuthFailure: true,
+            OriginalError: fmt.Errorf("failed to fetch Linear issue %s: %s", config.IssueKey, response.Status),
+        }
+    }
+
+    var result issueResponse
+    if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+        return nil, fmt.Errorf("failed to decode Linear response for issue %s (%s): %v", config.IssueKey, response.Status, err)
+    }
+
+    if len(result.Errors) > 0 {
+        isAuthFailure := false
+        isNotFound := false
+        var messages []string
+        for _, graphQLErr := range result.Errors {
+            switch graphQLErr.Extensions.Code {
+            case "AUTHENTICATION_ERROR":
+                isAuthFailure = true
+            case "ENTITY_NOT_FOUND", "NOT_FOUND":
+                isNotFound = true
+            }
+            messages = append(messages, graphQLErr.Message)
+        }
+
+        return nil, &drivers.TrackerError{
+            IsAuthFailure: isAuthFailure,
+            IsNotFound:    isNotFound || (!isAuthFailure && issueMissing(result)),
+            OriginalError: fmt.Errorf("failed to fetch Linear issue %s: %s", config.IssueKey, strings.Join(messages, "; ")),
+        }
+    }
+
+    if result.Data == nil || result.Data.Issue == nil {
+        return nil, &drivers.TrackerError{
+            IsNotFound:    issueMissing(result),
+            OriginalError: fmt.Errorf("linear returned no issue for %s", config.IssueKey),
+        }
+    }
+
+    return &result, nil
+}
+
+// issueMissing reports whether the query ran and returned a null issue, which is how Linear answers an identifier it does not know
+func issueMissing(result issueResponse) bool {
+    return result.Data != nil && result.Data.Issue == nil
+}
+
+func getLinearInfo(ctx context.Context, config Configuration) (Information, error) {
+    response, err := getIssue(ctx, config)
+    if err != nil {
+        return Information{}, err
+    }
+
+    issue := response.Data.Issue
+    result := Information{
+        Title:       issue.Title,
+        Description: issue.Description,
+        Labels:      issue.Labels.Nodes,
+    }
+
+    if issue.Parent != nil {
+        result.ParentPrefix = issue.Parent.Identifier
+    }
+
+    return result, nil
+}
+
+func apiURL() string {
+    if url := os.Getenv(envAPIURL); url != "" {
+        return url
+    }
+
+    return defaultAPIURL
+}
+
+func syncOptions() drivers.SyncOptions {
+    return drivers.NewSyncOptions("Linear", "LINEAR", "linear-sync-complete")
+}
+
+func issueLabelSyncEnabled() bool {
+    return strings.ToLower(os.Getenv("OPT_ENABLE_LINEAR_ISSUE_LABELS")) == "true"
+}
@@ -0,0 +1,147 @@
+package linear
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+)
+
+func newGraphQLServer(t *testing.T, handler func(w http.ResponseWriter, request graphQLRequest)) *httptest.Server {
+    t.Helper()
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Authorization") == "" {
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+
+        var request graphQLRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            t.Fatalf("failed to decode GraphQL request: %v", err)
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        handler(w, request)
+    }))
+    t.Cleanup(server.Close)
+
+    return server
+}
+
+func TestGetIssueKeyFromBranchName(t *testing.T) {
+    tests := []struct {
+        branchName string
+        expected   string
+    }{
+        {branchName: "jdoe/eng-123-add-export-endpoint", expected: "ENG-123"},
+        {branchName: "eng-7-update-readme", expected: "ENG-7"},
+        {branchName: "feature/ENG-123-add-export", expected: "ENG-123"},
+        {branchName: "jdoe/eng-42", expected: "ENG-42"},
+        {branchName: "jdoe/add-export", expected: ""},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            if actual := GetIssueKeyFromBranchName(tt.branchName); actual != tt.expected {
+                t.Errorf("GetIssueKeyFromBranchName() = %q, want %q", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestGetLinearInfo(t *testing.T) {
+    server := newGraphQLServer(t, func(w http.ResponseWriter, request graphQLRequest) {
+        if request.Variables["id"] == "ENG-500" {
+            w.Write([]byte(`{"data":null,"errors":[{"message":"Rate limit exceeded; issue lookup not found in quota","extensions":{"code":"RATELIMITED"}}]}`))
+            return
+        }
+
+        if request.Variables["id"] != "ENG-123" {
+            w.Write([]byte(`{"data":{"issue":null},"errors":[{"message":"Entity not found","extensions":{"code":"INVALID_INPUT"}}]}`))
+            return
+        }
+
+        w.Write([]byte(`{"data":{"issue":{
+            "identifier":"ENG-123",
+            "title":"Add export endpoint",
+            "description":"Export all records as CSV.",
+            "parent":{"identifier":"ENG-100"},
+            "labels":{"nodes":[{"name":"backend","color":"#5e6ad2"}]}
+        }}}`))
+    })
+
+    tests := []struct {
+        name            string
+        config          Configuration
+        expected        Information
+        wantErr         bool
+        wantNotFound    bool
+        wantAuthFailure bool
+    }{
+        {
+            name:   "issue with parent and labels",
+            config: Configuration{APIURL: server.URL, Token: "lin_api_test", IssueKey: "ENG-123"},
+            expected: Information{
+                ParentPrefix: "ENG-100",
+                Title:        "Add export endpoint",
+                Description:  "Export all records as CSV.",
+                Labels:       []Label{{Name: "backend", Color: "#5e6ad2"}},
+            },
+        },
+        {
+            name:         "missing issue",
+            config:       Configuration{APIURL: server.URL, Token: "lin_api_test", IssueKey: "ENG-999"},
+            wantNotFound: true,
+        },
+        {
+            name:    "failed query is not a missing issue",
+            config:  Configuration{APIURL: server.URL, Token: "lin_api_test", IssueKey: "ENG-500"},
+            wantErr: true,
+        },
+        {
+            name:            "unauthorized",
+            config:          Configuration{APIURL: server.URL, IssueKey: "ENG-123"},
+            wantAuthFailure: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            info, err := getLinearInfo(context.Background(), tt.config)
+
+            if (err != nil) != (tt.wantErr || tt.wantNotFound || tt.wantAuthFailure) {
+                t.Fatalf("getLinearInfo() error = %v", err)
+            }
+
+            var trackerErr *drivers.TrackerError
+            errors.As(err, &trackerErr)
+
+            if trackerErr != nil && (trackerErr.IsNotFound != tt.wantNotFound || trackerErr.IsAuthFailure != tt.wantAuthFailure) {
+                t.Errorf("getLinearInfo() error = %+v, wantNotFound %v, wantAuthFailure %v", trackerErr, tt.wantNotFound, tt.wantAuthFailure)
+            }
+
+            if !reflect.DeepEqual(info, tt.expected) {
+                t.Errorf("getLinearInfo() = %+v, want %+v", info, tt.expected)
+            }
+        })
+    }
+}
+
+func TestGetLinearInfoAuthenticationError(t *testing.T) {
+    server := newGraphQLServer(t, func(w http.ResponseWriter, request graphQLRequest) {
+        w.Write([]byte(`{"errors":[{"message":"Authentication required","extensions":{"code":"AUTHENTICATION_ERROR"}}]}`))
+    })
+
+    _, err := getLinearInfo(context.Background(), Configuration{APIURL: server.URL, Token: "lin_api_revoked", IssueKey: "ENG-123"})
+
+    var trackerErr *drivers.TrackerError
+    if !errors.As(err, &trackerErr) || !trackerErr.IsAuthFailure {
+        t.Errorf("getLinearInfo() error = %v, want an authentication failure", err)
+    }
+}
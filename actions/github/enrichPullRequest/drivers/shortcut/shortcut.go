@@ -0,0 +1,235 @@
+package shortcut
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+)
+
+const Name = "shortcut"
+
+const envToken = "OPT_SHORTCUT_TOKEN"
+const envAPIURL = "OPT_SHORTCUT_API_URL"
+
+const defaultAPIURL = "https://api.app.shortcut.com/api/v3"
+
+var regexStory = regexp.MustCompile(`(?i)(?:^|/)sc-([0-9]+)(?:[/-]|$)`)
+
+type Configuration struct {
+    APIURL  string
+    Token   string
+    StoryID int
+}
+
+type Information struct {
+    Title       string
+    Description string
+    Epic        string
+    Iteration   string
+    StoryType   string
+}
+
+type story struct {
+    Name        string `json:"name"`
+    Description string `json:"description"`
+    StoryType   string `json:"story_type"`
+    EpicID      *int   `json:"epic_id"`
+    IterationID *int   `json:"iteration_id"`
+}
+
+type namedResource struct {
+    Name string `json:"name"`
+}
+
+type driver struct{}
+
+func init() {
+    drivers.Register(driver{})
+}
+
+func (driver) Name() string {
+    return Name
+}
+
+func (driver) Validate() error {
+    if os.Getenv(envToken) == "" {
+        return fmt.Errorf("%s must be set when configured strategy is '%s'", envToken, Name)
+    }
+
+    return nil
+}
+
+func (driver) Enrich(ctx context.Context, gh github.GitHub) error {
+    return Format(ctx, gh)
+}
+
+func Format(ctx context.Context, gh github.GitHub) error {
+    options := syncOptions()
+    if synced, err := options.AlreadySynced(ctx, gh); err != nil || synced {
+        return err
+    }
+
+    branchName, err := gh.GetBranchName(ctx)
+    if err != nil {
+        return err
+    }
+
+    storyID := GetStoryIDFromBranchName(branchName)
+    if storyID == 0 {
+        return fmt.Errorf("%w: branch %s does not reference a Shortcut story", drivers.ErrNotFound, branchName)
+    }
+
+    config := Configuration{
+        APIURL:  apiURL(),
+        Token:   os.Getenv(envToken),
+        StoryID: storyID,
+    }
+
+    shortcut, err := getShortcutInfo(ctx, config)
+    if err != nil {
+        return drivers.FetchFailed(ctx, gh, drivers.Tracker{
+            Name: "Shortcut",
+            Item: "story",
+            Solutions: []string{
+                "Check that the `" + envToken + "` environment variable is set correctly",
+                "Ensure the token owner has access to the story: `" + storyKey(storyID) + "`",
+            },
+        }, storyKey(storyID), err)
+    }
+
+    return drivers.Sync(ctx, gh, drivers.Issue{
+        Key:         storyKey(storyID),
+        Title:       shortcut.Title,
+        Description: formatDescription(shortcut),
+        Type:        shortcut.StoryType,
+        Sprint:      shortcut.Iteration,
+    }, options)
+}
+
+// GetStoryIDFromBranchName returns the story ID referenced by branches like feature/sc-12345-add-export, or 0 if there is none
+func GetStoryIDFromBranchName(branchName string) int {
+    matches := regexStory.FindStringSubmatch(branchName)
+    if matches == nil {
+        return 0
+    }
+
+    storyID, err := strconv.Atoi(matches[1])
+    if err != nil {
+        return 0
+    }
+
+    return storyID
+}
+
+func storyKey(storyID int) string {
+    return fmt.Sprintf("sc-%d", storyID)
+}
+
+func formatDescription(shortcut Information) string {
+    var header []string
+    if shortcut.Epic != "" {
+        header = append(header, "**Epic:** "+shortcut.Epic)
+    }
+
+    if shortcut.Iteration != "" {
+        header = append(header, "**Iteration:** "+shortcut.Iteration)
+    }
+
+    if len(header) == 0 {
+        return shortcut.Description
+    }
+
+    return strings.Join(header, "\n") + "\n\n" + shortcut.Description
+}
+
+func get(ctx context.Context, config Configuration, path string, result interface{}) error {
+    request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(config.APIURL, "/")+path, nil)
+    if err != nil {
+        return err
+    }
+
+    request.Header.Set("Accept", "application/json")
+    request.Header.Set("Shortcut-Token", config.Token)
+
+    response, err := http.DefaultClient.Do(request)
+    if err != nil {
+        return fmt.Errorf("failed to fetch %s: %v", path, err)
+    }
+    defer response.Body.Close()
+
+    if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+        return &drivers.TrackerError{
+            IsAuthFailure: true,
+            OriginalError: fmt.Errorf("failed to fetch %s: %s", path, response.Status),
+        }
+    }
+
+    if response.StatusCode != http.StatusOK {
+        return &drivers.TrackerError{
+            IsNotFound:    response.StatusCode == http.StatusNotFound,
+            OriginalError: fmt.Errorf("failed to fetch %s: %s", path, response.Status),
+        }
+    }
+
+    if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+        return fmt.Errorf("failed to decode %s: %v", path, err)
+    }
+
+    return nil
+}
+
+func getShortcutInfo(ctx context.Context, config Configuration) (Information, error) {
+    var currentStory story
+    if err := get(ctx, config, fmt.Sprintf("/stories/%d", config.StoryID), &currentStory); err != nil {
+        return Information{}, err
+    }
+
+    result := Information{
+        Title:       currentStory.Name,
+        Description: currentStory.Description,
+        StoryType:   currentStory.StoryType,
+    }
+
+    // Epic and iteration only add context, so failures are logged and skipped
+    if currentStory.EpicID != nil {
+        var epic namedResource
+        if err := get(ctx, config, fmt.Sprintf("/epics/%d", *currentStory.EpicID), &epic); err != nil {
+            logger.Errorf("Failed to get epic info: %v", err)
+        } else {
+            result.Epic = epic.Name
+        }
+    }
+
+    if currentStory.IterationID != nil {
+        var iteration namedResource
+        if err := get(ctx, config, fmt.Sprintf("/iterations/%d", *currentStory.IterationID), &iteration); err != nil {
+            logger.Errorf("Failed to get iteration info: %v", err)
+        } else {
+            result.Iteration = iteration.Name
+        }
+    }
+
+    return result, nil
+}
+
+func apiURL() string {
+    if url := os.Getenv(envAPIURL); url != "" {
+        return url
+    }
+
+    return defaultAPIURL
+}
+
+func syncOptions() drivers.SyncOptions {
+    return drivers.NewSyncOptions("Shortcut", "SHORTCUT", "shortcut-sync-complete")
+}
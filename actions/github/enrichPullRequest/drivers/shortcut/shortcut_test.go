@@ -0,0 +1,121 @@
+package shortcut
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
+)
+
+func TestGetStoryIDFromBranchName(t *testing.T) {
+    tests := []struct {
+        branchName string
+        expected   int
+    }{
+        {branchName: "feature/sc-12345-add-export", expected: 12345},
+        {branchName: "jdoe/sc-42/fix-login", expected: 42},
+        {branchName: "sc-7-update-readme", expected: 7},
+        {branchName: "feature/disc-12-something", expected: 0},
+        {branchName: "feature/PROJ-123-add-export", expected: 0},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            if actual := GetStoryIDFromBranchName(tt.branchName); actual != tt.expected {
+                t.Errorf("GetStoryIDFromBranchName() = %d, want %d", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestGetShortcutInfo(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Shortcut-Token") != "test-token" {
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        switch r.URL.Path {
+        case "/api/v3/stories/12345":
+            w.Write([]byte(`{"name":"Add export endpoint","description":"Export all records.","epic_id":10,"iteration_id":20}`))
+        case "/api/v3/stories/500":
+            w.Write([]byte(`{"name":"Standalone story","description":"","epic_id":null,"iteration_id":null}`))
+        case "/api/v3/epics/10":
+            w.Write([]byte(`{"name":"Reporting"}`))
+        case "/api/v3/iterations/20":
+            w.Write([]byte(`{"name":"Sprint 14"}`))
+        default:
+            w.WriteHeader(http.StatusNotFound)
+        }
+    }))
+    defer server.Close()
+
+    apiURL := server.URL + "/api/v3"
+
+    tests := []struct {
+        name            string
+        config          Configuration
+        expected        Information
+        wantNotFound    bool
+        wantAuthFailure bool
+    }{
+        {
+            name:   "story with epic and iteration",
+            config: Configuration{APIURL: apiURL, Token: "test-token", StoryID: 12345},
+            expected: Information{
+                Title:       "Add export endpoint",
+                Description: "Export all records.",
+                Epic:        "Reporting",
+                Iteration:   "Sprint 14",
+            },
+        },
+        {
+            name:     "story without epic or iteration",
+            config:   Configuration{APIURL: apiURL, Token: "test-token", StoryID: 500},
+            expected: Information{Title: "Standalone story"},
+        },
+        {
+            name:         "missing story",
+            config:       Configuration{APIURL: apiURL, Token: "test-token", StoryID: 999},
+            wantNotFound: true,
+        },
+        {
+            name:            "invalid token",
+            config:          Configuration{APIURL: apiURL, Token: "revoked", StoryID: 12345},
+            wantAuthFailure: true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            info, err := getShortcutInfo(context.Background(), tt.config)
+
+            var trackerErr *drivers.TrackerError
+            if errors.As(err, &trackerErr) != (tt.wantNotFound || tt.wantAuthFailure) {
+                t.Fatalf("getShortcutInfo() error = %v", err)
+            }
+
+            if trackerErr != nil && (trackerErr.IsNotFound != tt.wantNotFound || trackerErr.IsAuthFailure != tt.wantAuthFailure) {
+                t.Errorf("getShortcutInfo() error = %+v, wantNotFound %v, wantAuthFailure %v", trackerErr, tt.wantNotFound, tt.wantAuthFailure)
+            }
+
+            if !reflect.DeepEqual(info, tt.expected) {
+                t.Errorf("getShortcutInfo() = %+v, want %+v", info, tt.expected)
+            }
+        })
+    }
+}
+
+func TestFormatDescription(t *testing.T) {
+    info := Information{Description: "Export all records.", Epic: "Reporting", Iteration: "Sprint 14"}
+    expected := "**Epic:** Reporting\n**Iteration:** Sprint 14\n\nExport all records."
+
+    if actual := formatDescription(info); actual != expected {
+        t.Errorf("formatDescription() = %q, want %q", actual, expected)
+    }
+}
@@ -0,0 +1,222 @@
+package drivers
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/EncoreDigitalGroup/golib/logger"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+)
+
+// Issue holds the information an issue tracker driver applies to a pull request
+type Issue struct {
+    Key          string
+    RelatedKeys  []string
+    Title        string
+    Description  string
+    ParentPrefix string
+    Type         string
+    Sprint       string
+    EpicKey      string
+    EpicName     string
+
+    // KeepTitle puts Title in the pull request title as written, for trackers whose titles are already cased
+    KeepTitle bool
+
+    // EpicInTitle puts the epic ahead of the issue key in the default title
+    EpicInTitle bool
+}
+
+// EnvMultipleIssues turns on treating further issue keys in the branch name, as in PROJ-12-PROJ-15-shared-fix, as related issues
+const EnvMultipleIssues = "OPT_JIRA_MULTIPLE_ISSUES"
+
+// MultipleIssuesEnabled reports whether a pull request may cover more than one issue
+func MultipleIssuesEnabled() bool {
+    return strings.ToLower(os.Getenv(EnvMultipleIssues)) == "true"
+}
+
+// SyncOptions controls how an Issue is written to the pull request
+type SyncOptions struct {
+    Source           string
+    SyncDescription  bool
+    SyncLabel        bool
+    LabelName        string
+    LabelDescription string
+}
+
+// NewSyncOptions reads the OPT_ENABLE_<PREFIX>_SYNC_DESCRIPTION, OPT_ENABLE_<PREFIX>_SYNC_LABEL and OPT_<PREFIX>_SYNC_LABEL_NAME inputs
+// for a driver, such as prefix LINEAR for the Linear driver
+func NewSyncOptions(source string, envPrefix string, defaultLabelName string) SyncOptions {
+    labelName := os.Getenv("OPT_" + envPrefix + "_SYNC_LABEL_NAME")
+    if labelName == "" {
+        labelName = defaultLabelName
+    }
+
+    return SyncOptions{
+        Source:           source,
+        SyncDescription:  strings.ToLower(os.Getenv("OPT_ENABLE_"+envPrefix+"_SYNC_DESCRIPTION")) == "true",
+        SyncLabel:        strings.ToLower(os.Getenv("OPT_ENABLE_"+envPrefix+"_SYNC_LABEL")) == "true",
+        LabelName:        labelName,
+        LabelDescription: "Indicates that " + source + " synchronization has been completed for this PR",
+    }
+}
+
+// AlreadySynced reports whether the sync label is enabled and already present on the pull request
+func (options SyncOptions) AlreadySynced(ctx context.Context, gh github.GitHub) (bool, error) {
+    if !options.SyncLabel {
+        return false, nil
+    }
+
+    hasLabel, err := gh.HasLabel(ctx, options.LabelName)
+    if err != nil {
+        return false, err
+    }
+
+    if hasLabel {
+        logger.Info("PR already has '" + options.LabelName + "' label, skipping " + options.Source + " sync")
+    }
+
+    return hasLabel, nil
+}
+
+// Sync formats the pull request title from the issue, optionally syncs the description, and applies the sync label. The sync label is
+// only applied once the pull request has been updated, so a failed update is tried again on the next run.
+func Sync(ctx context.Context, gh github.GitHub, issue Issue, options SyncOptions) error {
+    newPRTitle := gh.ApplyFormatting(ctx, github.TitleData{
+        IssueKey:      issue.Key,
+        RelatedKeys:   issue.RelatedKeys,
+        IssueName:     issue.Title,
+        ParentKey:     issue.ParentPrefix,
+        IssueType:     issue.Type,
+        Sprint:        issue.Sprint,
+        EpicKey:       issue.EpicKey,
+        EpicName:      issue.EpicName,
+        EpicInTitle:   issue.EpicInTitle,
+        KeepIssueName: issue.KeepTitle,
+    })
+
+    syncTitle, err := gh.TitleSyncAllowed(ctx)
+    if err != nil {
+        return err
+    }
+
+    switch {
+    case options.SyncDescription && syncTitle:
+        logger.Info("Updating PR title and description from " + options.Source + " issue")
+        err = gh.UpdatePR(ctx, newPRTitle, issue.Description)
+    case options.SyncDescription:
+        logger.Info("Updating PR description from " + options.Source + " issue")
+        err = gh.UpdatePRDescription(ctx, issue.Description)
+    case syncTitle:
+        logger.Info("Updating PR title from " + options.Source + " issue")
+        err = gh.UpdatePRTitle(ctx, newPRTitle)
+    }
+
+    if err != nil {
+        return err
+    }
+
+    if options.SyncLabel {
+        return ApplyLabel(ctx, gh, options.LabelName, options.LabelDescription, "0052cc")
+    }
+
+    return nil
+}
+
+// ApplyLabel creates the label if needed and adds it to the pull request. When GitHub refuses, which usually means the token cannot
+// manage labels, a comment on the pull request explains what is missing in place of the label and the run carries on. Requests that ran
+// out of retries, and comments that cannot be posted either, are returned as errors.
+func ApplyLabel(ctx context.Context, gh github.GitHub, name string, description string, color string) error {
+    var comment string
+    err := gh.EnsureLabelExists(ctx, name, description, color)
+    if err != nil {
+        comment = "The label `" + name + "` does not exist in this repository and we encountered an " +
+            "error when attempting to create it.\n\n" +
+            "Please ensure the access token provided has permission to manage labels."
+    } else if err = gh.AddLabelToPR(ctx, name); err != nil {
+        comment = "We failed to add the `" + name + "` label to this PR.\n\n" +
+            "Please ensure the access token provided has permission to manage labels."
+    }
+
+    if err == nil {
+        return nil
+    }
+
+    if api.Exhausted(err) {
+        return err
+    }
+
+    logger.Errorf("%v; commenting on the PR instead", err)
+    if commentErr := gh.AddPRComment(ctx, comment); commentErr != nil {
+        return errors.Join(err, commentErr)
+    }
+
+    return nil
+}
+
+// Comment posts a comment explaining a failure on the pull request. The returned error joins the failure with the comment's own error
+// when the comment cannot be posted, and is the failure otherwise.
+func Comment(ctx context.Context, gh github.GitHub, failure error, comment string) error {
+    if err := gh.AddPRComment(ctx, comment); err != nil {
+        return errors.Join(failure, fmt.Errorf("unable to explain the failure on the PR: %w", err))
+    }
+
+    return failure
+}
+
+// TrackerError is returned by a driver's tracker client when the tracker rejects the credentials or has no such issue
+type TrackerError struct {
+    IsAuthFailure bool
+    IsNotFound    bool
+    OriginalError error
+}
+
+func (e *TrackerError) Error() string {
+    return e.OriginalError.Error()
+}
+
+func (e *TrackerError) Unwrap() error {
+    return e.OriginalError
+}
+
+// Tracker names an issue tracker in the errors and comments FetchFailed writes
+type Tracker struct {
+    Name string
+
+    // Item is what the tracker calls an issue, such as "story" or "work item"
+    Item string
+
+    // Solutions are listed in the comment posted when authentication fails
+    Solutions []string
+}
+
+// FetchFailed turns an error fetching an issue from the tracker into the driver's result. A missing issue is ErrNotFound, so the chain
+// can try the next driver; an authentication or any other failure is explained in a comment on the pull request.
+func FetchFailed(ctx context.Context, gh github.GitHub, tracker Tracker, key string, err error) error {
+    logger.Errorf("Failed to get %s %s %s: %v", tracker.Name, tracker.Item, key, err)
+
+    var trackerErr *TrackerError
+    if errors.As(err, &trackerErr) && trackerErr.IsNotFound {
+        return fmt.Errorf("%w: %s %s %s does not exist", ErrNotFound, tracker.Name, tracker.Item, key)
+    }
+
+    if errors.As(err, &trackerErr) && trackerErr.IsAuthFailure {
+        comment := "**" + tracker.Name + " Authentication Failed**\n\n" +
+            "Unable to authenticate with " + tracker.Name + " to fetch " + tracker.Item + " information. " +
+            "Please verify that the credentials are correctly configured and have not expired or been revoked.\n\n" +
+            "**Possible solutions:**\n" +
+            "- " + strings.Join(tracker.Solutions, "\n- ")
+
+        return Comment(ctx, gh, fmt.Errorf("%s authentication failed: %w", tracker.Name, err), comment)
+    }
+
+    comment := "Failed to get information from " + tracker.Name + ".\n\n" +
+        "Please check the GitHub Action logs for specific error information."
+
+    return Comment(ctx, gh, fmt.Errorf("failed to get information from %s for %s %s: %w", tracker.Name, tracker.Item, key, err), comment)
+}
@@ -3,11 +3,11 @@ module github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullReque
 go 1.24.1
 
 require (
+	github.com/EncoreDigitalGroup/ci-workflows/actions/github/support v0.0.0
 	github.com/EncoreDigitalGroup/golib v0.1.1
 	github.com/ctreminiom/go-atlassian v1.6.1
 	github.com/google/go-github/v70 v70.0.0
 	golang.org/x/oauth2 v0.28.0
-	golang.org/x/text v0.23.0
 )
 
 require (
@@ -32,4 +32,7 @@ require (
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/sys v0.30.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
 )
+
+replace github.com/EncoreDigitalGroup/ci-workflows/actions/github/support => ../support
@@ -1,80 +1,113 @@
 package main
 
 import (
+    "context"
+    "errors"
     "os"
-    "strconv"
-    "strings"
 
     "github.com/EncoreDigitalGroup/golib/logger"
 
     "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers"
-    branchname "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/branch_name"
-    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/jira"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/branch"
     "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/github"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/event"
 )
 
 var gh github.GitHub
 
-const envGHRepository = "GH_REPOSITORY"
-const envPRNumber = "PR_NUMBER"
 const envStrategy = "OPT_FMT_STRATEGY"
+const envStrategyLabelPrefix = "OPT_STRATEGY_LABEL_PREFIX"
 
 // Retrieve environment variables
 var strategy = os.Getenv(envStrategy)
-var repo = os.Getenv(envGHRepository)
-var prNumberStr = os.Getenv(envPRNumber)
-var parts = strings.Split(repo, "/")
 
 // Main function to execute the program
 func main() {
-    validStrategy := drivers.Validate(strategy)
-
-    if !validStrategy {
-        logger.Errorf("Invalid strategy: %s", strategy)
+    chain, err := drivers.Chain(strategy)
+    if err != nil {
+        logger.Error(err.Error())
         os.Exit(1)
     }
 
     checkEnvVars()
-    repoOwner := parts[0]
-    repoName := parts[1]
 
-    // Convert PR_NUMBER to integer
-    prNumber, err := strconv.Atoi(prNumberStr)
+    // The pull request comes from the event payload unless GH_REPOSITORY, PR_NUMBER, or BRANCH_NAME override it
+    pullRequest, err := event.Load()
+    if err == nil {
+        err = pullRequest.Validate()
+    }
+
     if err != nil {
-        logger.Errorf(envPRNumber+" is not a valid integer: %v", err)
+        logger.Error(err.Error())
         os.Exit(1)
     }
 
-    gh = github.New(repoOwner, repoName, prNumber)
+    for _, driver := range chain {
+        if err := driver.Validate(); err != nil {
+            logger.Errorf("Invalid configuration for strategy %s: %v", driver.Name(), err)
+            os.Exit(1)
+        }
+    }
 
-    if strategy == drivers.BranchName {
-        branchname.Format(gh)
+    if err := branch.LoadPatterns(); err != nil {
+        logger.Error(err.Error())
+        os.Exit(1)
     }
 
-    if strategy == drivers.Jira {
-        jira.Format(gh)
+    if err := github.ValidateTitleTemplate(); err != nil {
+        logger.Error(err.Error())
+        os.Exit(1)
     }
-}
 
-func checkEnvVars() {
-    isMissingVar := false
-    if strategy == "" {
-        logger.Error(envStrategy + " environment variable is not set")
-        isMissingVar = true
+    ctx := context.Background()
+    gh, err = github.New(ctx, pullRequest)
+    if err != nil {
+        logger.Error(err.Error())
+        os.Exit(1)
     }
 
-    if repo == "" {
-        logger.Error(envGHRepository + " environment variable is not set")
-        isMissingVar = true
+    enrichedBy, err := drivers.Run(ctx, gh, chain)
+    if err == nil {
+        err = applyStrategyLabel(ctx, enrichedBy)
     }
 
-    if prNumberStr == "" {
-        logger.Error(envPRNumber + " environment variable is not set")
-        isMissingVar = true
+    os.Exit(summarize(enrichedBy, err))
+}
+
+// applyStrategyLabel labels the pull request with the strategy that enriched it, when a label prefix is configured
+func applyStrategyLabel(ctx context.Context, enrichedBy string) error {
+    labelPrefix := os.Getenv(envStrategyLabelPrefix)
+    if labelPrefix == "" {
+        return nil
     }
 
-    if len(parts) != 2 {
-        logger.Error(envGHRepository + " must be in the format owner/repo")
+    label := labelPrefix + enrichedBy
+
+    return drivers.ApplyLabel(ctx, gh, label, "Indicates which strategy enriched this PR", "ededed")
+}
+
+// summarize logs the outcome of the run and returns its exit code. Finding no issue to enrich the pull request with is not a failure.
+func summarize(enrichedBy string, err error) int {
+    switch {
+    case errors.Is(err, drivers.ErrNotFound):
+        logger.Info("No strategy was able to enrich the pull request")
+        return 0
+    case err != nil && enrichedBy != "":
+        logger.Errorf("Pull request enriched by strategy %s, but the run failed: %v", enrichedBy, err)
+        return 1
+    case err != nil:
+        logger.Errorf("Enriching the pull request failed: %v", err)
+        return 1
+    }
+
+    logger.Infof("Pull request enriched by strategy: %s", enrichedBy)
+    return 0
+}
+
+func checkEnvVars() {
+    isMissingVar := false
+    if strategy == "" {
+        logger.Error(envStrategy + " environment variable is not set")
         isMissingVar = true
     }
 
@@ -0,0 +1,11 @@
+package main
+
+// Drivers register themselves with the drivers package when imported.
+import (
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/azure_boards"
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/branch_name"
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/github_issues"
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/jira"
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/linear"
+    _ "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/drivers/shortcut"
+)
@@ -0,0 +1,119 @@
+package branch
+
+import (
+    "fmt"
+    "os"
+    "regexp"
+    "strings"
+)
+
+const envBranchPatterns = "OPT_BRANCH_PATTERNS"
+
+// DefaultPatterns are used when no patterns are configured
+var DefaultPatterns = []string{
+    `^(?P<type>epic|feature|bugfix|hotfix)/(?P<key>[A-Z]+-[0-9]+)-(?P<name>.+)$`,
+    `^(?P<key>[A-Z]+-[0-9]+)-(?P<name>.+)$`,
+}
+
+var patterns = mustCompile(DefaultPatterns)
+
+var regexLeadingKey = regexp.MustCompile(`^([A-Z][A-Z0-9]*-[0-9]+)-`)
+
+// Match holds the named capture groups of the first pattern matching a branch name
+type Match struct {
+    Type string
+    Key  string
+    Name string
+}
+
+// LoadPatterns replaces the default patterns with the newline-separated patterns in OPT_BRANCH_PATTERNS, if set
+func LoadPatterns() error {
+    value := os.Getenv(envBranchPatterns)
+    if strings.TrimSpace(value) == "" {
+        return nil
+    }
+
+    var expressions []string
+    for _, line := range strings.Split(value, "\n") {
+        if line = strings.TrimSpace(line); line != "" {
+            expressions = append(expressions, line)
+        }
+    }
+
+    compiled, err := Compile(expressions)
+    if err != nil {
+        return fmt.Errorf("invalid %s: %w", envBranchPatterns, err)
+    }
+
+    patterns = compiled
+
+    return nil
+}
+
+// Compile compiles branch patterns, requiring each to define the named capture groups key and name
+func Compile(expressions []string) ([]*regexp.Regexp, error) {
+    var compiled []*regexp.Regexp
+    for _, expression := range expressions {
+        pattern, err := regexp.Compile(expression)
+        if err != nil {
+            return nil, fmt.Errorf("pattern %q does not compile: %v", expression, err)
+        }
+
+        for _, group := range []string{"key", "name"} {
+            if pattern.SubexpIndex(group) == -1 {
+                return nil, fmt.Errorf("pattern %q is missing the named capture group (?P<%s>...)", expression, group)
+            }
+        }
+
+        compiled = append(compiled, pattern)
+    }
+
+    return compiled, nil
+}
+
+// Parse matches the branch name against the configured patterns in order.
+// Issue keys are upper-cased so that patterns may match lowercase branch names.
+func Parse(branchName string) (Match, bool) {
+    for _, pattern := range patterns {
+        matches := pattern.FindStringSubmatch(branchName)
+        if matches == nil {
+            continue
+        }
+
+        match := Match{
+            Key:  strings.ToUpper(matches[pattern.SubexpIndex("key")]),
+            Name: matches[pattern.SubexpIndex("name")],
+        }
+
+        if index := pattern.SubexpIndex("type"); index != -1 {
+            match.Type = matches[index]
+        }
+
+        return match, true
+    }
+
+    return Match{}, false
+}
+
+// RelatedKeys splits further issue keys off the front of a branch name, so "PROJ-15-shared-fix" yields PROJ-15 and "shared-fix"
+func RelatedKeys(name string) ([]string, string) {
+    var keys []string
+    for {
+        matches := regexLeadingKey.FindStringSubmatch(name)
+        if matches == nil {
+            return keys, name
+        }
+
+        keys = append(keys, matches[1])
+        name = name[len(matches[0]):]
+    }
+}
+
+func mustCompile(expressions []string) []*regexp.Regexp {
+    compiled, err := Compile(expressions)
+    if err != nil {
+        panic(err)
+    }
+
+    return compiled
+}
@@ -0,0 +1,105 @@
+package branch
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestParseDefaultPatterns(t *testing.T) {
+    tests := []struct {
+        branchName string
+        expected   Match
+        matched    bool
+    }{
+        {branchName: "feature/PROJ-123-user-authentication", expected: Match{Type: "feature", Key: "PROJ-123", Name: "user-authentication"}, matched: true},
+        {branchName: "TASK-789-api-improvements", expected: Match{Key: "TASK-789", Name: "api-improvements"}, matched: true},
+        {branchName: "chore/PROJ-12-update-deps", matched: false},
+        {branchName: "proj-12-lowercase", matched: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            match, ok := Parse(tt.branchName)
+            if ok != tt.matched || match != tt.expected {
+                t.Errorf("Parse() = %+v, %v, want %+v, %v", match, ok, tt.expected, tt.matched)
+            }
+        })
+    }
+}
+
+func TestLoadPatterns(t *testing.T) {
+    t.Cleanup(func() {
+        patterns = mustCompile(DefaultPatterns)
+    })
+
+    t.Setenv(envBranchPatterns, `
+        ^(?P<type>feat|chore|release)/(?P<key>[A-Z]+-[0-9]+)-(?P<name>.+)$
+        (?i)^(?P<key>[a-z]+-[0-9]+)-(?P<name>.+)$
+    `)
+
+    if err := LoadPatterns(); err != nil {
+        t.Fatalf("LoadPatterns() error = %v", err)
+    }
+
+    tests := []struct {
+        branchName string
+        expected   Match
+    }{
+        {branchName: "chore/PROJ-12-update-deps", expected: Match{Type: "chore", Key: "PROJ-12", Name: "update-deps"}},
+        {branchName: "proj-12-lowercase", expected: Match{Key: "PROJ-12", Name: "lowercase"}},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.branchName, func(t *testing.T) {
+            match, ok := Parse(tt.branchName)
+            if !ok || match != tt.expected {
+                t.Errorf("Parse() = %+v, %v, want %+v", match, ok, tt.expected)
+            }
+        })
+    }
+}
+
+func TestLoadPatternsRejectsInvalidPatterns(t *testing.T) {
+    tests := []struct {
+        name    string
+        pattern string
+        wantErr string
+    }{
+        {name: "does not compile", pattern: `^(?P<key>[A-Z+-(?P<name>.+)$`, wantErr: "does not compile"},
+        {name: "missing key group", pattern: `^(?P<name>.+)$`, wantErr: "(?P<key>...)"},
+        {name: "missing name group", pattern: `^(?P<key>[A-Z]+-[0-9]+)$`, wantErr: "(?P<name>...)"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envBranchPatterns, tt.pattern)
+
+            err := LoadPatterns()
+            if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+                t.Errorf("LoadPatterns() error = %v, want error containing %q", err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestRelatedKeys(t *testing.T) {
+    tests := []struct {
+        name         string
+        expectedKeys []string
+        expectedName string
+    }{
+        {name: "shared-fix", expectedName: "shared-fix"},
+        {name: "PROJ-15-shared-fix", expectedKeys: []string{"PROJ-15"}, expectedName: "shared-fix"},
+        {name: "PROJ-15-OPS-3-shared-fix", expectedKeys: []string{"PROJ-15", "OPS-3"}, expectedName: "shared-fix"},
+        {name: "utf-8-support", expectedName: "utf-8-support"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            keys, name := RelatedKeys(tt.name)
+            if strings.Join(keys, ",") != strings.Join(tt.expectedKeys, ",") || name != tt.expectedName {
+                t.Errorf("RelatedKeys() = %v, %q, want %v, %q", keys, name, tt.expectedKeys, tt.expectedName)
+            }
+        })
+    }
+}
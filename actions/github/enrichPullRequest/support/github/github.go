@@ -2,33 +2,83 @@ package github
 
 import (
     "context"
+    "encoding/base64"
+    "errors"
     "fmt"
+    "io"
+    "net/http"
     "os"
     "strings"
     "sync"
+    "text/template"
 
     "github.com/EncoreDigitalGroup/golib/logger"
     "github.com/google/go-github/v70/github"
     "golang.org/x/oauth2"
-    "golang.org/x/text/cases"
-    "golang.org/x/text/language"
+
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/enrichPullRequest/support/branch"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/auth"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/conventional"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/event"
+    "github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/issuename"
 )
 
-const envGHToken = "GH_TOKEN"
-const envBranchName = "BRANCH_NAME"
+const envTitleMode = "OPT_FMT_TITLE_MODE"
+const envConventionalTypes = "OPT_FMT_CONVENTIONAL_TYPES"
+const envTitleTemplate = "OPT_FMT_TITLE_TEMPLATE"
+const envPreserveManualTitle = "OPT_PRESERVE_MANUAL_TITLE"
+const envForceTitleSyncLabel = "OPT_FORCE_TITLE_SYNC_LABEL"
+
+const titleMarkerStart = "<!-- ENRICH_PR_TITLE: "
+const titleMarkerEnd = " -->"
+const jiraStartMarker = "<!-- JIRA_SYNC_START -->"
+const jiraEndMarker = "<!-- JIRA_SYNC_END -->"
+
+const titleModeConventional = "conventional"
+const defaultTitleTemplate = `{{if .ParentKey}}[{{.ParentKey}}]{{else if .EpicInTitle}}[{{or .EpicName .EpicKey}}]{{end}}[{{.IssueKey}}]{{range .RelatedKeys}}[{{.}}]{{end}} {{.IssueName}}`
+
+// TitleData holds the values available to the PR title template
+type TitleData struct {
+    IssueKey    string
+    RelatedKeys []string
+    IssueName   string
+    ParentKey   string
+    IssueType   string
+    BranchType  string
+    Sprint      string
+    EpicKey     string
+    EpicName    string
+
+    // EpicInTitle puts the epic's name, or its key when the epic has no name, ahead of the issue key in the default title
+    EpicInTitle bool
+
+    // KeepIssueName leaves IssueName as written, for trackers whose titles are already cased, instead of formatting it like a branch name
+    KeepIssueName bool
+}
 
-// GitHub interface defines the contract for GitHub operations
+// GitHub interface defines the contract for GitHub operations. Methods that call the API return its error rather than logging it, so
+// the caller decides whether to fall back, carry on, or fail the run.
 type GitHub interface {
-    GetBranchName() (string, error)
-    BranchNameMatchesPRTitle(currentPRTitle string) bool
-    GetPRInformation() *github.PullRequest
-    UpdatePR(newPRTitle string, newPRDescription string)
-    UpdatePRTitle(newPRTitle string)
-    ApplyFormatting(issueKey string, issueName string) string
-    HasLabel(labelName string) bool
-    AddLabelToPR(labelName string)
-    EnsureLabelExists(labelName string, description string, color string)
-    AddPRComment(comment string)
+    GetBranchName(ctx context.Context) (string, error)
+    BranchNameMatchesPRTitle(ctx context.Context, currentPRTitle string) (bool, error)
+    GetPRInformation(ctx context.Context) (*github.PullRequest, error)
+    UpdatePR(ctx context.Context, newPRTitle string, newPRDescription string) error
+    UpdatePRTitle(ctx context.Context, newPRTitle string) error
+    UpdatePRDescription(ctx context.Context, newPRDescription string) error
+    TitleSyncAllowed(ctx context.Context) (bool, error)
+    ApplyFormatting(ctx context.Context, title TitleData) string
+    HasLabel(ctx context.Context, labelName string) (bool, error)
+    AddLabelToPR(ctx context.Context, labelName string) error
+    RemoveLabelFromPR(ctx context.Context, labelName string) error
+    EnsureLabelExists(ctx context.Context, labelName string, description string, color string) error
+    AddPRComment(ctx context.Context, comment string) error
+    GetIssue(ctx context.Context, issueNumber int) (*github.Issue, error)
+    GetPRCommitMessages(ctx context.Context) ([]string, error)
+    RequestReviewers(ctx context.Context, logins []string) error
+    AddAssignees(ctx context.Context, logins []string) error
+    SetMilestone(ctx context.Context, title string) error
+    PullRequestAction() (string, bool)
 }
 
 // GitHubClient implements the GitHub interface
@@ -37,100 +87,285 @@ type GitHubClient struct {
     repositoryOwner   string
     repositoryName    string
     pullRequestNumber int
+    headRef           string
     pullRequestInfo   *github.PullRequest
+    action            string
+    merged            bool
 }
 
 var (
-    client *github.Client
-    once   sync.Once
+    client    *github.Client
+    clientErr error
+    once      sync.Once
 )
 
-func New(repoOwner string, repoName string, prNumber int) GitHub {
+// New creates a client for the pull request the run acts on. The pull request from the event payload, when there is one, is used in
+// place of fetching it.
+func New(ctx context.Context, pullRequest *event.PullRequest) (GitHub, error) {
     once.Do(func() {
-        githubToken := os.Getenv(envGHToken)
-
-        if githubToken == "" {
-            logger.Error(envGHToken + " is not set")
-            os.Exit(1)
+        var ts oauth2.TokenSource
+        ts, clientErr = auth.TokenSource(ctx, pullRequest.Owner, pullRequest.Repo)
+        if clientErr != nil {
+            return
         }
 
-        ctx := context.Background()
-        ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
-        tc := oauth2.NewClient(ctx, ts)
-        client = github.NewClient(tc)
+        client, clientErr = api.NewClient(ts)
     })
 
+    if clientErr != nil {
+        return nil, clientErr
+    }
+
     return &GitHubClient{
         client:            client,
-        repositoryOwner:   repoOwner,
-        repositoryName:    repoName,
-        pullRequestNumber: prNumber,
-        pullRequestInfo:   nil,
-    }
+        repositoryOwner:   pullRequest.Owner,
+        repositoryName:    pullRequest.Repo,
+        pullRequestNumber: pullRequest.Number,
+        headRef:           pullRequest.HeadRef,
+        pullRequestInfo:   pullRequest.Payload,
+        action:            pullRequest.Action,
+        merged:            pullRequest.Merged,
+    }, nil
 }
 
-func (gh *GitHubClient) GetBranchName() (string, error) {
-    branchName := os.Getenv(envBranchName)
+// PullRequestAction returns the action of the event that triggered the run, and whether the pull request it names is merged
+func (gh *GitHubClient) PullRequestAction() (string, bool) {
+    return gh.action, gh.merged
+}
 
+// GetBranchName returns the PR's head branch, which merge_group and issue_comment payloads leave out, so it is then read from the PR
+func (gh *GitHubClient) GetBranchName(ctx context.Context) (string, error) {
+    if gh.headRef != "" {
+        return gh.headRef, nil
+    }
+
+    pullRequestInformation, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return "", err
+    }
+
+    branchName := pullRequestInformation.GetHead().GetRef()
     if branchName == "" {
-        logger.Error(envBranchName + " is not set")
-        return "", fmt.Errorf("%s is not set", envBranchName)
+        return "", fmt.Errorf("unable to determine the pull request branch name; set %s", event.EnvBranchName)
     }
 
+    gh.headRef = branchName
+
     return branchName, nil
 }
 
-func (gh *GitHubClient) BranchNameMatchesPRTitle(currentPRTitle string) bool {
-    pullRequest, _, err := gh.client.PullRequests.Get(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber)
+func (gh *GitHubClient) BranchNameMatchesPRTitle(ctx context.Context, currentPRTitle string) (bool, error) {
+    pullRequestInformation, err := gh.GetPRInformation(ctx)
     if err != nil {
-        logger.Errorf("Failed to get pullRequest request: %v", err)
-        return false
+        return false, err
     }
 
-    if currentPRTitle == *pullRequest.Title {
+    if currentPRTitle == pullRequestInformation.GetTitle() {
         logger.Info("Pull Request Titles Match; No Need to Update.")
-        return true
+        return true, nil
     }
 
     logger.Info("Pull Request Titles Do Not Match; Update Needed.")
-    return false
+    return false, nil
 }
 
-func (gh *GitHubClient) GetPRInformation() *github.PullRequest {
+// GetPRInformation returns the pull request, fetching it the first time when the event payload did not include it
+func (gh *GitHubClient) GetPRInformation(ctx context.Context) (*github.PullRequest, error) {
     if gh.pullRequestInfo != nil {
-        return gh.pullRequestInfo
+        return gh.pullRequestInfo, nil
     }
 
-    pullRequestInformation, _, err := gh.client.PullRequests.Get(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber)
+    pullRequestInformation, _, err := gh.client.PullRequests.Get(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber)
     if err != nil {
-        logger.Errorf("Failed to get pullRequest information: %v", err)
-        os.Exit(1)
+        return nil, fmt.Errorf("failed to get pull request #%d: %w", gh.pullRequestNumber, err)
     }
 
     gh.pullRequestInfo = pullRequestInformation
 
-    return gh.pullRequestInfo
+    return gh.pullRequestInfo, nil
 }
 
-func (gh *GitHubClient) UpdatePRTitle(newPRTitle string) {
+// latestPRInformation fetches the pull request again before it is edited. The event payload is a snapshot from when the run was
+// triggered, so a body built from it would undo description edits made since.
+func (gh *GitHubClient) latestPRInformation(ctx context.Context) (*github.PullRequest, error) {
+    pullRequestInformation, _, err := gh.client.PullRequests.Get(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get pull request #%d: %w", gh.pullRequestNumber, err)
+    }
+
+    gh.pullRequestInfo = pullRequestInformation
+
+    return pullRequestInformation, nil
+}
+
+func (gh *GitHubClient) UpdatePRTitle(ctx context.Context, newPRTitle string) error {
+    pullRequestInformation, err := gh.latestPRInformation(ctx)
+    if err != nil {
+        return err
+    }
+
+    edit := &github.PullRequest{Title: &newPRTitle}
+
+    // Record the title we wrote so later runs can tell whether someone edited it by hand. Only the marker changes, and the body is
+    // left out of the edit when the marker already holds this title or manual titles are not preserved.
+    if finalDescription := gh.withTitleMarker(pullRequestInformation.GetBody(), newPRTitle); finalDescription != pullRequestInformation.GetBody() {
+        edit.Body = &finalDescription
+    }
+
     logger.Infof("Attempting to Update Pull Request Title to: %s", newPRTitle)
 
-    _, _, err := gh.client.PullRequests.Edit(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, &github.PullRequest{
-        Title: &newPRTitle,
-    })
+    pullRequest, _, err := gh.client.PullRequests.Edit(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, edit)
 
     if err != nil {
-        logger.Errorf("Failed to update pull request prTitle: %v", err)
-        return
+        return fmt.Errorf("failed to update pull request title: %w", err)
     }
 
+    gh.pullRequestInfo = pullRequest
     logger.Infof("Updated Pull Request Title to: %s", newPRTitle)
+
+    return nil
 }
 
-func (gh *GitHubClient) processDescriptionWithMarkers(existingBody string, newPRDescription string) string {
-    const jiraStartMarker = "<!-- JIRA_SYNC_START -->"
-    const jiraEndMarker = "<!-- JIRA_SYNC_END -->"
+func (gh *GitHubClient) UpdatePRDescription(ctx context.Context, newPRDescription string) error {
+    pullRequestInformation, err := gh.latestPRInformation(ctx)
+    if err != nil {
+        return err
+    }
+
+    finalDescription := gh.processDescriptionWithMarkers(pullRequestInformation.GetBody(), newPRDescription)
+
+    pullRequest, _, err := gh.client.PullRequests.Edit(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, &github.PullRequest{
+        Body: &finalDescription,
+    })
+
+    if err != nil {
+        return fmt.Errorf("failed to update pull request description: %w", err)
+    }
+
+    gh.pullRequestInfo = pullRequest
+    logger.Info("Updated Pull Request Description")
+
+    return nil
+}
+
+// TitleSyncAllowed reports whether the PR title may be overwritten. A title edited by hand since the last sync is left alone
+// unless the force label is on the PR, in which case the label is removed so the override only applies once. A force label that cannot
+// be removed is an error, since leaving it would keep overwriting the title on every run.
+func (gh *GitHubClient) TitleSyncAllowed(ctx context.Context) (bool, error) {
+    if !preserveManualTitle() {
+        return true, nil
+    }
+
+    forceLabel := forceTitleSyncLabel()
+    hasForceLabel, err := gh.HasLabel(ctx, forceLabel)
+    if err != nil {
+        return false, err
+    }
+
+    if hasForceLabel {
+        logger.Infof("PR has '%s' label, forcing title sync", forceLabel)
+        if err := gh.RemoveLabelFromPR(ctx, forceLabel); err != nil {
+            return false, err
+        }
+
+        return true, nil
+    }
 
+    pullRequestInformation, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return false, err
+    }
+
+    lastTitle, ok := LastSyncedTitle(pullRequestInformation.GetBody())
+    if !ok || lastTitle == pullRequestInformation.GetTitle() {
+        return true, nil
+    }
+
+    logger.Infof("PR title was edited manually since the last sync; skipping title update. Add the '%s' label to force a re-sync.", forceLabel)
+    return false, nil
+}
+
+func preserveManualTitle() bool {
+    return strings.ToLower(os.Getenv(envPreserveManualTitle)) == "true"
+}
+
+func forceTitleSyncLabel() string {
+    label := os.Getenv(envForceTitleSyncLabel)
+
+    if label == "" {
+        return "force-title-sync"
+    }
+
+    return label
+}
+
+// LastSyncedTitle returns the title recorded by withTitleMarker, if the body has one
+func LastSyncedTitle(body string) (string, bool) {
+    startIndex := strings.Index(body, titleMarkerStart)
+    if startIndex == -1 {
+        return "", false
+    }
+
+    encoded := body[startIndex+len(titleMarkerStart):]
+    endIndex := strings.Index(encoded, titleMarkerEnd)
+    if endIndex == -1 {
+        return "", false
+    }
+
+    title, err := base64.StdEncoding.DecodeString(encoded[:endIndex])
+    if err != nil {
+        return "", false
+    }
+
+    return string(title), true
+}
+
+// StripSyncedContent removes the synced description block and title marker, leaving only what people wrote in the body
+func StripSyncedContent(body string) string {
+    startIndex := strings.Index(body, jiraStartMarker)
+    endIndex := strings.Index(body, jiraEndMarker)
+    if startIndex != -1 && endIndex > startIndex {
+        body = body[:startIndex] + body[endIndex+len(jiraEndMarker):]
+    }
+
+    if startIndex := strings.Index(body, titleMarkerStart); startIndex != -1 {
+        if endIndex := strings.Index(body[startIndex:], titleMarkerEnd); endIndex != -1 {
+            body = body[:startIndex] + body[startIndex+endIndex+len(titleMarkerEnd):]
+        }
+    }
+
+    return strings.TrimSpace(body)
+}
+
+// withTitleMarker records the title in a hidden comment at the end of the body, replacing any earlier marker.
+// The title is base64 encoded so characters like "-->" cannot break out of the comment.
+func withTitleMarker(body string, title string) string {
+    marker := titleMarkerStart + base64.StdEncoding.EncodeToString([]byte(title)) + titleMarkerEnd
+
+    if startIndex := strings.Index(body, titleMarkerStart); startIndex != -1 {
+        if endIndex := strings.Index(body[startIndex:], titleMarkerEnd); endIndex != -1 {
+            body = body[:startIndex] + body[startIndex+endIndex+len(titleMarkerEnd):]
+        }
+    }
+
+    body = strings.TrimSpace(body)
+    if body == "" {
+        return marker
+    }
+
+    return body + "\n\n" + marker
+}
+
+// withTitleMarker adds the title marker only when manual titles are preserved, since nothing reads it otherwise
+func (gh *GitHubClient) withTitleMarker(body string, title string) string {
+    if !preserveManualTitle() {
+        return body
+    }
+
+    return withTitleMarker(body, title)
+}
+
+func (gh *GitHubClient) processDescriptionWithMarkers(existingBody string, newPRDescription string) string {
     if existingBody != "" {
         // Check if Jira markers already exist
         startIndex := strings.Index(existingBody, jiraStartMarker)
@@ -151,95 +386,148 @@ func (gh *GitHubClient) processDescriptionWithMarkers(existingBody string, newPR
     }
 }
 
-func (gh *GitHubClient) UpdatePR(newPRTitle string, newPRDescription string) {
-    pullRequestInformation := gh.GetPRInformation()
+func (gh *GitHubClient) UpdatePR(ctx context.Context, newPRTitle string, newPRDescription string) error {
+    pullRequestInformation, err := gh.latestPRInformation(ctx)
+    if err != nil {
+        return err
+    }
 
     var existingBody string
     if pullRequestInformation.Body != nil {
         existingBody = *pullRequestInformation.Body
     }
 
-    finalDescription := gh.processDescriptionWithMarkers(existingBody, newPRDescription)
+    finalDescription := gh.withTitleMarker(gh.processDescriptionWithMarkers(existingBody, newPRDescription), newPRTitle)
 
     logger.Infof("Attempting to Update Pull Request Title to: %s", newPRTitle)
 
-    _, _, err := gh.client.PullRequests.Edit(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, &github.PullRequest{
+    pullRequest, _, err := gh.client.PullRequests.Edit(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, &github.PullRequest{
         Title: &newPRTitle,
         Body:  &finalDescription,
     })
 
     if err != nil {
-        logger.Errorf("Failed to update pull request: %v", err)
-    } else {
-        logger.Infof("Updated Pull Request Title to: %s", newPRTitle)
-        logger.Info("Updated Pull Request Description")
-    }
-}
-
-func (gh *GitHubClient) ApplyFormatting(issueKey string, issueName string) string {
-    // Replace hyphens with spaces and capitalize each word
-    formattedIssueName := strings.ReplaceAll(issueName, "-", " ")
-    titleCaser := cases.Title(language.English)
-    formattedIssueName = titleCaser.String(formattedIssueName)
-
-    defaultExceptions := map[string]string{
-        "Api":          "API",
-        "Css":          "CSS",
-        "Db":           "DB",
-        "Html":         "HTML",
-        "Rest":         "REST",
-        "Rockrms":      "RockRMS",
-        "Mpc":          "MPC",
-        "Myportal":     "MyPortal",
-        "Pco":          "PCO",
-        "Php":          "PHP",
-        "Phpstan":      "PHPStan",
-        "Servicepoint": "ServicePoint",
-        "Themekit":     "ThemeKit",
-        "Uri":          "URI",
-        "Webcms":       "WebCMS",
-        "Webui":        "WebUI",
-    }
-
-    if userDefinedExceptions := os.Getenv("OPT_FMT_WORDS"); userDefinedExceptions != "" {
-        pairs := strings.Split(userDefinedExceptions, ",")
-        for _, pair := range pairs {
-            kv := strings.SplitN(pair, ":", 2)
-            if len(kv) == 2 {
-                key := strings.TrimSpace(kv[0])
-                value := strings.TrimSpace(kv[1])
-                defaultExceptions[key] = value
-            }
-        }
+        return fmt.Errorf("failed to update pull request: %w", err)
+    }
+
+    gh.pullRequestInfo = pullRequest
+    logger.Infof("Updated Pull Request Title to: %s", newPRTitle)
+    logger.Info("Updated Pull Request Description")
+
+    return nil
+}
+
+func (gh *GitHubClient) ApplyFormatting(ctx context.Context, title TitleData) string {
+    conventional := ConventionalTitleMode()
+    if !title.KeepIssueName {
+        title.IssueName = issuename.Format(title.IssueName, os.Getenv("OPT_FMT_WORDS"), conventional)
     }
-    words := strings.Fields(formattedIssueName)
-    for i, word := range words {
-        if val, ok := defaultExceptions[word]; ok {
-            words[i] = val
+
+    if title.BranchType == "" {
+        if branchName, err := gh.GetBranchName(ctx); err == nil {
+            title.BranchType = branchTypeFromName(branchName)
         }
     }
-    formattedIssueName = strings.Join(words, " ")
 
-    return fmt.Sprintf("[%s] %s", issueKey, formattedIssueName)
+    if conventional && os.Getenv(envTitleTemplate) == "" {
+        return applyConventionalFormatting(title)
+    }
+
+    return renderTitle(title)
+}
+
+// ValidateTitleTemplate checks that OPT_FMT_TITLE_TEMPLATE parses and only references TitleData fields
+func ValidateTitleTemplate() error {
+    titleTemplate, err := parseTitleTemplate()
+    if err != nil {
+        return err
+    }
+
+    if err := titleTemplate.Execute(io.Discard, TitleData{}); err != nil {
+        return fmt.Errorf("invalid %s: %v", envTitleTemplate, err)
+    }
+
+    return nil
+}
+
+func parseTitleTemplate() (*template.Template, error) {
+    text := os.Getenv(envTitleTemplate)
+    if text == "" {
+        text = defaultTitleTemplate
+    }
+
+    titleTemplate, err := template.New("title").Parse(text)
+    if err != nil {
+        return nil, fmt.Errorf("invalid %s: %v", envTitleTemplate, err)
+    }
+
+    return titleTemplate, nil
+}
+
+func renderTitle(title TitleData) string {
+    titleTemplate, err := parseTitleTemplate()
+    if err != nil {
+        logger.Errorf("%v; using the default title template", err)
+        titleTemplate = template.Must(template.New("title").Parse(defaultTitleTemplate))
+    }
+
+    var rendered strings.Builder
+    if err := titleTemplate.Execute(&rendered, title); err != nil {
+        logger.Errorf("Failed to render title template: %v; using the default title template", err)
+        rendered.Reset()
+        template.Must(template.New("title").Parse(defaultTitleTemplate)).Execute(&rendered, title)
+    }
+
+    return strings.TrimSpace(rendered.String())
 }
 
-func (gh *GitHubClient) HasLabel(labelName string) bool {
-    pullRequestInformation := gh.GetPRInformation()
+// ConventionalTitleMode reports whether titles should be formatted as Conventional Commits
+func ConventionalTitleMode() bool {
+    return strings.ToLower(os.Getenv(envTitleMode)) == titleModeConventional
+}
+
+func applyConventionalFormatting(title TitleData) string {
+    commitType := conventional.Type(title.BranchType, os.Getenv(envConventionalTypes))
+    return conventional.Title(commitType, append([]string{title.IssueKey}, title.RelatedKeys...), title.IssueName)
+}
+
+func branchTypeFromName(branchName string) string {
+    if match, ok := branch.Parse(branchName); ok && match.Type != "" {
+        return match.Type
+    }
+
+    if prefix, _, found := strings.Cut(branchName, "/"); found {
+        return prefix
+    }
+
+    return ""
+}
+
+func (gh *GitHubClient) HasLabel(ctx context.Context, labelName string) (bool, error) {
+    pullRequestInformation, err := gh.GetPRInformation(ctx)
+    if err != nil {
+        return false, err
+    }
 
     for _, label := range pullRequestInformation.Labels {
         if label.Name != nil && *label.Name == labelName {
-            return true
+            return true, nil
         }
     }
 
-    return false
+    return false, nil
 }
 
-func (gh *GitHubClient) EnsureLabelExists(labelName string, description string, color string) {
-    _, _, err := gh.client.Issues.GetLabel(context.Background(), gh.repositoryOwner, gh.repositoryName, labelName)
+// EnsureLabelExists creates the label in the repository unless it is already there
+func (gh *GitHubClient) EnsureLabelExists(ctx context.Context, labelName string, description string, color string) error {
+    _, _, err := gh.client.Issues.GetLabel(ctx, gh.repositoryOwner, gh.repositoryName, labelName)
     if err == nil {
         // Label already exists
-        return
+        return nil
+    }
+
+    if !IsNotFound(err) {
+        return fmt.Errorf("failed to look up label '%s': %w", labelName, err)
     }
 
     label := &github.Label{
@@ -248,43 +536,145 @@ func (gh *GitHubClient) EnsureLabelExists(labelName string, description string,
         Color:       &color,
     }
 
-    _, _, err = gh.client.Issues.CreateLabel(context.Background(), gh.repositoryOwner, gh.repositoryName, label)
+    _, _, err = gh.client.Issues.CreateLabel(ctx, gh.repositoryOwner, gh.repositoryName, label)
     if err != nil {
-        logger.Errorf("Failed to create label '%s': %v", labelName, err)
-        prComment := "The label `" + labelName + "` does not exist in this repository and we encountered an " +
-            "error when attempting to create it.\n\n" +
-            "Please ensure the access token provided has permission to manage labels."
+        return fmt.Errorf("failed to create label '%s': %w", labelName, err)
+    }
 
-        gh.AddPRComment(prComment)
+    logger.Infof("Created label '%s' in repository", labelName)
 
-    } else {
-        logger.Infof("Created label '%s' in repository", labelName)
-    }
+    return nil
 }
 
-func (gh *GitHubClient) AddLabelToPR(labelName string) {
+func (gh *GitHubClient) AddLabelToPR(ctx context.Context, labelName string) error {
     labels := []string{labelName}
 
-    _, _, err := gh.client.Issues.AddLabelsToIssue(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, labels)
+    _, _, err := gh.client.Issues.AddLabelsToIssue(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, labels)
     if err != nil {
-        logger.Errorf("Failed to add label '%s' to PR: %v", labelName, err)
-        prComment := "We failed to add the `" + labelName + "` label to this PR.\n\n" +
-            "Please ensure the access token provided has permission to manage labels."
-        gh.AddPRComment(prComment)
-    } else {
-        logger.Infof("Added label '%s' to PR #%d", labelName, gh.pullRequestNumber)
+        return fmt.Errorf("failed to add label '%s' to PR: %w", labelName, err)
+    }
+
+    logger.Infof("Added label '%s' to PR #%d", labelName, gh.pullRequestNumber)
+
+    return nil
+}
+
+func (gh *GitHubClient) RemoveLabelFromPR(ctx context.Context, labelName string) error {
+    _, err := gh.client.Issues.RemoveLabelForIssue(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, labelName)
+    if err != nil {
+        return fmt.Errorf("failed to remove label '%s' from PR: %w", labelName, err)
     }
+
+    logger.Infof("Removed label '%s' from PR #%d", labelName, gh.pullRequestNumber)
+
+    return nil
 }
 
-func (gh *GitHubClient) AddPRComment(comment string) {
+func (gh *GitHubClient) AddPRComment(ctx context.Context, comment string) error {
     issueComment := &github.IssueComment{
         Body: &comment,
     }
 
-    _, _, err := gh.client.Issues.CreateComment(context.Background(), gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, issueComment)
+    _, _, err := gh.client.Issues.CreateComment(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, issueComment)
     if err != nil {
-        logger.Errorf("Failed to add comment to PR: %v", err)
-    } else {
-        logger.Infof("Added comment to PR #%d", gh.pullRequestNumber)
+        return fmt.Errorf("failed to add comment to PR: %w", err)
+    }
+
+    logger.Infof("Added comment to PR #%d", gh.pullRequestNumber)
+
+    return nil
+}
+
+func (gh *GitHubClient) GetIssue(ctx context.Context, issueNumber int) (*github.Issue, error) {
+    issue, _, err := gh.client.Issues.Get(ctx, gh.repositoryOwner, gh.repositoryName, issueNumber)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get issue #%d: %w", issueNumber, err)
+    }
+
+    return issue, nil
+}
+
+func (gh *GitHubClient) GetPRCommitMessages(ctx context.Context) ([]string, error) {
+    var messages []string
+    options := &github.ListOptions{PerPage: 100}
+    for {
+        commits, response, err := gh.client.PullRequests.ListCommits(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, options)
+        if err != nil {
+            return nil, fmt.Errorf("failed to list commits for PR #%d: %w", gh.pullRequestNumber, err)
+        }
+
+        for _, commit := range commits {
+            messages = append(messages, commit.GetCommit().GetMessage())
+        }
+
+        if response.NextPage == 0 {
+            return messages, nil
+        }
+
+        options.Page = response.NextPage
+    }
+}
+
+func (gh *GitHubClient) RequestReviewers(ctx context.Context, logins []string) error {
+    reviewers := github.ReviewersRequest{Reviewers: logins}
+
+    _, _, err := gh.client.PullRequests.RequestReviewers(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, reviewers)
+    if err != nil {
+        return fmt.Errorf("failed to request reviews from %s: %w", strings.Join(logins, ", "), err)
+    }
+
+    logger.Infof("Requested reviews from %s on PR #%d", strings.Join(logins, ", "), gh.pullRequestNumber)
+
+    return nil
+}
+
+func (gh *GitHubClient) AddAssignees(ctx context.Context, logins []string) error {
+    _, _, err := gh.client.Issues.AddAssignees(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, logins)
+    if err != nil {
+        return fmt.Errorf("failed to assign %s to PR: %w", strings.Join(logins, ", "), err)
     }
+
+    logger.Infof("Assigned %s to PR #%d", strings.Join(logins, ", "), gh.pullRequestNumber)
+
+    return nil
+}
+
+// SetMilestone assigns the PR to the open milestone with the given title. Milestones are not created, so a title with no milestone is
+// logged and skipped.
+func (gh *GitHubClient) SetMilestone(ctx context.Context, title string) error {
+    options := &github.MilestoneListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+    for {
+        milestones, response, err := gh.client.Issues.ListMilestones(ctx, gh.repositoryOwner, gh.repositoryName, options)
+        if err != nil {
+            return fmt.Errorf("failed to list milestones: %w", err)
+        }
+
+        for _, milestone := range milestones {
+            if !strings.EqualFold(milestone.GetTitle(), title) {
+                continue
+            }
+
+            request := &github.IssueRequest{Milestone: milestone.Number}
+            if _, _, err := gh.client.Issues.Edit(ctx, gh.repositoryOwner, gh.repositoryName, gh.pullRequestNumber, request); err != nil {
+                return fmt.Errorf("failed to set milestone '%s' on PR: %w", milestone.GetTitle(), err)
+            }
+
+            logger.Infof("Set milestone '%s' on PR #%d", milestone.GetTitle(), gh.pullRequestNumber)
+
+            return nil
+        }
+
+        if response.NextPage == 0 {
+            logger.Infof("No open milestone named '%s'; leaving the PR milestone unchanged", title)
+            return nil
+        }
+
+        options.Page = response.NextPage
+    }
+}
+
+// IsNotFound reports whether err is a GitHub API 404 response
+func IsNotFound(err error) bool {
+    var errorResponse *github.ErrorResponse
+    return errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound
 }
@@ -0,0 +1,306 @@
+package github
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/google/go-github/v70/github"
+)
+
+func TestApplyFormatting(t *testing.T) {
+    tests := []struct {
+        name              string
+        titleMode         string
+        conventionalTypes string
+        branchName        string
+        issueKey          string
+        issueName         string
+        expected          string
+    }{
+        {
+            name:       "default mode",
+            branchName: "feature/PROJ-12-add-export-api",
+            issueKey:   "PROJ-12",
+            issueName:  "add-export-api",
+            expected:   "[PROJ-12] Add Export API",
+        },
+        {
+            name:       "conventional feature",
+            titleMode:  "conventional",
+            branchName: "feature/PROJ-12-add-export-api",
+            issueKey:   "PROJ-12",
+            issueName:  "add-export-api",
+            expected:   "feat(PROJ-12): add export API",
+        },
+        {
+            name:       "conventional bugfix from issue summary",
+            titleMode:  "conventional",
+            branchName: "bugfix/PROJ-13-login",
+            issueKey:   "PROJ-13",
+            issueName:  "Fix Login Redirect",
+            expected:   "fix(PROJ-13): fix login redirect",
+        },
+        {
+            name:       "conventional hotfix is breaking",
+            titleMode:  "conventional",
+            branchName: "hotfix/PROJ-14-drop-legacy-endpoint",
+            issueKey:   "PROJ-14",
+            issueName:  "drop-legacy-endpoint",
+            expected:   "fix(PROJ-14)!: drop legacy endpoint",
+        },
+        {
+            name:       "conventional without branch type falls back",
+            titleMode:  "conventional",
+            branchName: "PROJ-15-update-readme",
+            issueKey:   "PROJ-15",
+            issueName:  "update-readme",
+            expected:   "chore(PROJ-15): update readme",
+        },
+        {
+            name:              "conventional with custom mapping",
+            titleMode:         "conventional",
+            conventionalTypes: "feature:feature, *:feat",
+            branchName:        "feature/PROJ-16-add-export",
+            issueKey:          "PROJ-16",
+            issueName:         "add-export",
+            expected:          "feature(PROJ-16): add export",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envTitleMode, tt.titleMode)
+            t.Setenv(envConventionalTypes, tt.conventionalTypes)
+            t.Setenv("OPT_FMT_WORDS", "")
+            t.Setenv(envTitleTemplate, "")
+
+            gh := &GitHubClient{headRef: tt.branchName}
+            if actual := gh.ApplyFormatting(context.Background(), TitleData{IssueKey: tt.issueKey, IssueName: tt.issueName}); actual != tt.expected {
+                t.Errorf("ApplyFormatting() = %q, want %q", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestApplyFormattingWithTemplate(t *testing.T) {
+    tests := []struct {
+        name     string
+        template string
+        title    TitleData
+        expected string
+    }{
+        {
+            name:     "default template with parent",
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", ParentKey: "PROJ-1"},
+            expected: "[PROJ-1][PROJ-12] Add Export",
+        },
+        {
+            name:     "default template with related keys",
+            title:    TitleData{IssueKey: "PROJ-12", RelatedKeys: []string{"PROJ-15"}, IssueName: "shared-fix"},
+            expected: "[PROJ-12][PROJ-15] Shared Fix",
+        },
+        {
+            name:     "default template with epic",
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", EpicKey: "PROJ-1", EpicName: "Checkout Redesign", EpicInTitle: true},
+            expected: "[Checkout Redesign][PROJ-12] Add Export",
+        },
+        {
+            name:     "default template with unnamed epic",
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", EpicKey: "PROJ-1", EpicInTitle: true},
+            expected: "[PROJ-1][PROJ-12] Add Export",
+        },
+        {
+            name:     "epic left out of the title by default",
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", EpicKey: "PROJ-1", EpicName: "Checkout Redesign"},
+            expected: "[PROJ-12] Add Export",
+        },
+        {
+            name:     "issue name kept as written",
+            title:    TitleData{IssueKey: "#42", IssueName: "Fix iOS OAuth bug", KeepIssueName: true},
+            expected: "[#42] Fix iOS OAuth bug",
+        },
+        {
+            name:     "custom layout",
+            template: `{{.IssueKey}} | {{.IssueName}}{{with .ParentKey}} (Epic: {{.}}){{end}}`,
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", ParentKey: "PROJ-1"},
+            expected: "PROJ-12 | Add Export (Epic: PROJ-1)",
+        },
+        {
+            name:     "branch and issue type",
+            template: `{{.BranchType}}: [{{.IssueKey}}] {{.IssueName}} ({{.IssueType}})`,
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export", IssueType: "Story"},
+            expected: "feature: [PROJ-12] Add Export (Story)",
+        },
+        {
+            name:     "invalid template falls back to default",
+            template: `{{.IssueKey`,
+            title:    TitleData{IssueKey: "PROJ-12", IssueName: "add-export"},
+            expected: "[PROJ-12] Add Export",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envTitleMode, "")
+            t.Setenv("OPT_FMT_WORDS", "")
+            t.Setenv(envTitleTemplate, tt.template)
+
+            gh := &GitHubClient{headRef: "feature/PROJ-12-add-export"}
+            if actual := gh.ApplyFormatting(context.Background(), tt.title); actual != tt.expected {
+                t.Errorf("ApplyFormatting() = %q, want %q", actual, tt.expected)
+            }
+        })
+    }
+}
+
+func TestValidateTitleTemplate(t *testing.T) {
+    tests := []struct {
+        template string
+        valid    bool
+    }{
+        {template: "", valid: true},
+        {template: `{{.IssueKey}} {{.IssueName}} {{.Sprint}}`, valid: true},
+        {template: `{{.IssueKey`, valid: false},
+        {template: `{{.Summary}}`, valid: false},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.template, func(t *testing.T) {
+            t.Setenv(envTitleTemplate, tt.template)
+
+            if err := ValidateTitleTemplate(); (err == nil) != tt.valid {
+                t.Errorf("ValidateTitleTemplate() error = %v, valid %v", err, tt.valid)
+            }
+        })
+    }
+}
+
+func TestTitleMarker(t *testing.T) {
+    tests := []struct {
+        name     string
+        body     string
+        title    string
+        expected string
+    }{
+        {
+            name:     "empty body",
+            body:     "",
+            title:    "[PROJ-12] Add Export",
+            expected: "<!-- ENRICH_PR_TITLE: W1BST0otMTJdIEFkZCBFeHBvcnQ= -->",
+        },
+        {
+            name:     "appends to existing body",
+            body:     "Some notes",
+            title:    "[PROJ-12] Add Export",
+            expected: "Some notes\n\n<!-- ENRICH_PR_TITLE: W1BST0otMTJdIEFkZCBFeHBvcnQ= -->",
+        },
+        {
+            name:     "replaces existing marker",
+            body:     "Some notes\n\n<!-- ENRICH_PR_TITLE: b2xk -->",
+            title:    "[PROJ-12] Add Export",
+            expected: "Some notes\n\n<!-- ENRICH_PR_TITLE: W1BST0otMTJdIEFkZCBFeHBvcnQ= -->",
+        },
+        {
+            name:     "title that would close the comment",
+            body:     "",
+            title:    "a --> b",
+            expected: "<!-- ENRICH_PR_TITLE: YSAtLT4gYg== -->",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            body := withTitleMarker(tt.body, tt.title)
+            if body != tt.expected {
+                t.Errorf("withTitleMarker() = %q, want %q", body, tt.expected)
+            }
+
+            if title, ok := LastSyncedTitle(body); !ok || title != tt.title {
+                t.Errorf("LastSyncedTitle() = %q, %v, want %q, true", title, ok, tt.title)
+            }
+        })
+    }
+
+    if _, ok := LastSyncedTitle("No marker here"); ok {
+        t.Error("LastSyncedTitle() found a marker in a body without one")
+    }
+}
+
+func TestStripSyncedContent(t *testing.T) {
+    body := "Also fixes PROJ-20\n\n<!-- JIRA_SYNC_START -->\nPROJ-15 details\n<!-- JIRA_SYNC_END -->\n\n<!-- ENRICH_PR_TITLE: W1BST0otMTJdIEFkZCBFeHBvcnQ= -->"
+
+    if actual := StripSyncedContent(body); actual != "Also fixes PROJ-20" {
+        t.Errorf("StripSyncedContent() = %q, want %q", actual, "Also fixes PROJ-20")
+    }
+}
+
+func TestUpdatePRTitleKeepsDescriptionEdits(t *testing.T) {
+    marker := withTitleMarker("", "[PROJ-12] Add Export")
+    tests := []struct {
+        name         string
+        preserve     string
+        currentBody  string
+        title        string
+        expectedBody string // empty when the edit should leave the body out
+    }{
+        {
+            name:         "body edited since the event",
+            preserve:     "true",
+            currentBody:  "Edited by hand\n\n" + marker,
+            title:        "[PROJ-12] Add Export API",
+            expectedBody: withTitleMarker("Edited by hand", "[PROJ-12] Add Export API"),
+        },
+        {
+            name:        "marker already holds the title",
+            preserve:    "true",
+            currentBody: "Edited by hand\n\n" + marker,
+            title:       "[PROJ-12] Add Export",
+        },
+        {
+            name:        "manual titles not preserved",
+            preserve:    "false",
+            currentBody: "Edited by hand",
+            title:       "[PROJ-12] Add Export API",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv(envPreserveManualTitle, tt.preserve)
+
+            var edit github.PullRequest
+            server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                if r.Method == http.MethodPatch {
+                    json.NewDecoder(r.Body).Decode(&edit)
+                }
+
+                json.NewEncoder(w).Encode(&github.PullRequest{Title: github.Ptr("Old title"), Body: github.Ptr(tt.currentBody)})
+            }))
+            defer server.Close()
+
+            client, _ := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+            gh := &GitHubClient{
+                client:            client,
+                repositoryOwner:   "owner",
+                repositoryName:    "repo",
+                pullRequestNumber: 1,
+                pullRequestInfo:   &github.PullRequest{Body: github.Ptr("Stale body from the event payload")},
+            }
+
+            if err := gh.UpdatePRTitle(context.Background(), tt.title); err != nil {
+                t.Fatalf("UpdatePRTitle() error = %v", err)
+            }
+
+            if edit.GetTitle() != tt.title {
+                t.Errorf("UpdatePRTitle() sent title %q, want %q", edit.GetTitle(), tt.title)
+            }
+
+            if (edit.Body == nil) != (tt.expectedBody == "") || edit.GetBody() != tt.expectedBody {
+                t.Errorf("UpdatePRTitle() sent body %q, want %q", edit.GetBody(), tt.expectedBody)
+            }
+        })
+    }
+}
@@ -5,33 +5,28 @@ import (
 	"fmt"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/EncoreDigitalGroup/golib/logger"
 	"github.com/google/go-github/v70/github"
-	"golang.org/x/oauth2"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
+
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/auth"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/conventional"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/event"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/issuename"
 )
 
-const envGHToken = "GH_TOKEN"
-const envGHRepository = "GH_REPOSITORY"
-const envPRNumber = "PR_NUMBER"
-const envBranchName = "BRANCH_NAME"
+const envTitleMode = "CI_FMT_TITLE_MODE"
+const envConventionalTypes = "CI_FMT_CONVENTIONAL_TYPES"
+
+const titleModeConventional = "conventional"
 
-// Retrieve environment variables
-var githubToken = os.Getenv(envGHToken)
-var repo = os.Getenv(envGHRepository)
-var prNumberStr = os.Getenv(envPRNumber)
-var branchName = os.Getenv(envBranchName)
-var parts = strings.Split(repo, "/")
+var branchName = ""
 
-// Initialize GitHub client with OAuth2 authentication
+// GitHub client, authenticated once the repository is known
 var ctx = context.Background()
-var ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
-var tc = oauth2.NewClient(ctx, ts)
-var client = github.NewClient(tc)
+var client *github.Client
 
 // Define regular expressions for title formatting
 var regexWithIssueType = regexp.MustCompile(`^(epic|feature|bugfix|hotfix)/([A-Z]+-[0-9]+)-(.+)$`)
@@ -40,44 +35,61 @@ var pullRequestTitle = ""
 
 // Main function to execute the program
 func main() {
-	if githubToken == "" {
-		logger.Error(envGHToken + " environment variable is not set")
+	// The pull request comes from the event payload unless GH_REPOSITORY, PR_NUMBER, or BRANCH_NAME override it
+	pullRequest, err := event.Load()
+	if err == nil {
+		err = pullRequest.Validate()
 	}
 
-	if repo == "" {
-		logger.Error(envGHRepository + " environment variable is not set")
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
 	}
+	repoOwner := pullRequest.Owner
+	repoName := pullRequest.Repo
+	prNumber := pullRequest.Number
 
-	if prNumberStr == "" {
-		logger.Error(envPRNumber + " environment variable is not set")
+	// Initialize GitHub client with a token, or a GitHub App installation token for the repository, against the API the workflow runs on
+	ts, err := auth.TokenSource(ctx, repoOwner, repoName)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
 	}
-
-	if branchName == "" {
-		logger.Error(envBranchName + " environment variable is not set")
+	client, err = api.NewClient(ts)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
 	}
 
-	if len(parts) != 2 {
-		logger.Error(envGHRepository + " must be in the format owner/repo")
-	}
-	repoOwner := parts[0]
-	repoName := parts[1]
+	// Payloads without the title or head branch, such as merge_group, need the pull request itself
+	if pullRequest.Title == "" || pullRequest.HeadRef == "" {
+		current, _, err := client.PullRequests.Get(ctx, repoOwner, repoName, prNumber)
+		if err != nil {
+			logger.Errorf("Failed to get pullRequest request: %v", err)
+			os.Exit(1)
+		}
 
-	// Convert PR_NUMBER to integer
-	prNumber, err := strconv.Atoi(prNumberStr)
-	if err != nil {
-		logger.Errorf(envPRNumber+" is not a valid integer: %v", err)
+		if pullRequest.Title == "" {
+			pullRequest.Title = current.GetTitle()
+		}
+
+		if pullRequest.HeadRef == "" {
+			pullRequest.HeadRef = current.GetHead().GetRef()
+		}
 	}
+	branchName = pullRequest.HeadRef
 
 	// Main logic: update title if it doesn't match
-	if !branchNameMatches(repoOwner, repoName, prNumber) {
+	if !branchNameMatches(pullRequest.Title) {
 		fmt.Println("Pull Request Title Should Be Updated.")
 		updatePullRequestTitle(repoOwner, repoName, prNumber, branchName)
 	}
 }
 
 func formatTitle(title string) string {
-	var issueKey, issueName string
+	var issueType, issueKey, issueName string
 	if matches := regexWithIssueType.FindStringSubmatch(title); matches != nil {
+		issueType = matches[1]
 		issueKey = matches[2]
 		issueName = matches[3]
 	} else if matches := regexWithoutIssueType.FindStringSubmatch(title); matches != nil {
@@ -89,48 +101,14 @@ func formatTitle(title string) string {
 		return pullRequestTitle
 	}
 
-	// Replace hyphens with spaces and capitalize each word
-	formattedIssueName := strings.ReplaceAll(issueName, "-", " ")
-	titleCaser := cases.Title(language.English)
-	formattedIssueName = titleCaser.String(formattedIssueName)
-
-	defaultExceptions := map[string]string{
-		"Api":          "API",
-		"Css":          "CSS",
-		"Db":           "DB",
-		"Html":         "HTML",
-		"Rest":         "REST",
-		"Rockrms":      "RockRMS",
-		"Mpc":          "MPC",
-		"Myportal":     "MyPortal",
-		"Pco":          "PCO",
-		"Php":          "PHP",
-		"Phpstan":      "PHPStan",
-		"Servicepoint": "ServicePoint",
-		"Themekit":     "ThemeKit",
-		"Uri":          "URI",
-		"Webcms":       "WebCMS",
-		"Webui":        "WebUI",
-	}
+	conventionalMode := strings.ToLower(os.Getenv(envTitleMode)) == titleModeConventional
 
-	if userDefinedExceptions := os.Getenv("CI_FMT_WORDS"); userDefinedExceptions != "" {
-		pairs := strings.Split(userDefinedExceptions, ",")
-		for _, pair := range pairs {
-			kv := strings.SplitN(pair, ":", 2)
-			if len(kv) == 2 {
-				key := strings.TrimSpace(kv[0])
-				value := strings.TrimSpace(kv[1])
-				defaultExceptions[key] = value
-			}
-		}
-	}
-	words := strings.Fields(formattedIssueName)
-	for i, word := range words {
-		if val, ok := defaultExceptions[word]; ok {
-			words[i] = val
-		}
+	// Conventional Commit subjects are lower case
+	formattedIssueName := issuename.Format(issueName, os.Getenv("CI_FMT_WORDS"), conventionalMode)
+
+	if conventionalMode {
+		return conventional.Title(conventional.Type(issueType, os.Getenv(envConventionalTypes)), []string{issueKey}, formattedIssueName)
 	}
-	formattedIssueName = strings.Join(words, " ")
 
 	return fmt.Sprintf("[%s] %s", issueKey, formattedIssueName)
 }
@@ -143,17 +121,15 @@ func updatePullRequestTitle(repoOwner string, repoName string, prNumber int, prT
 		Title: &formattedTitle,
 	})
 	if err != nil {
+		// The client already retried rate limits and gateway errors, so the title is not going to be updated by this run
 		logger.Errorf("Failed to update pull request prTitle: %v", err)
+		os.Exit(1)
 	}
 	logger.Infof("Updated Pull Request Title to: %s", formattedTitle)
 }
 
-func branchNameMatches(repoOwner string, repoName string, prNumber int) bool {
-	pullRequest, _, err := client.PullRequests.Get(ctx, repoOwner, repoName, prNumber)
-	if err != nil {
-		logger.Errorf("Failed to get pullRequest request: %v", err)
-	}
-	pullRequestTitle = *pullRequest.Title
+func branchNameMatches(currentTitle string) bool {
+	pullRequestTitle = currentTitle
 	logger.Info("Pull Request Title is:", pullRequestTitle)
 
 	formattedBranchName := formatTitle(branchName)
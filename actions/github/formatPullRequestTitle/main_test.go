@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestFormatTitle(t *testing.T) {
+	tests := []struct {
+		name              string
+		titleMode         string
+		conventionalTypes string
+		branchName        string
+		expected          string
+	}{
+		{name: "default mode", branchName: "feature/PROJ-12-add-export-api", expected: "[PROJ-12] Add Export API"},
+		{name: "default mode without type", branchName: "PROJ-12-add-export", expected: "[PROJ-12] Add Export"},
+		{name: "conventional feature", titleMode: "conventional", branchName: "feature/PROJ-12-add-export-api", expected: "feat(PROJ-12): add export API"},
+		{name: "conventional hotfix is breaking", titleMode: "conventional", branchName: "hotfix/PROJ-14-patch-login", expected: "fix(PROJ-14)!: patch login"},
+		{name: "conventional without type falls back", titleMode: "conventional", branchName: "PROJ-15-update-readme", expected: "chore(PROJ-15): update readme"},
+		{name: "conventional with custom mapping", titleMode: "conventional", conventionalTypes: "epic:feat!,*:docs", branchName: "epic/PROJ-1-new-portal", expected: "feat(PROJ-1)!: new portal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envTitleMode, tt.titleMode)
+			t.Setenv(envConventionalTypes, tt.conventionalTypes)
+			t.Setenv("CI_FMT_WORDS", "")
+
+			if actual := formatTitle(tt.branchName); actual != tt.expected {
+				t.Errorf("formatTitle() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
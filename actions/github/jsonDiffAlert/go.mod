@@ -3,6 +3,7 @@ module github.com/EncoreDigitalGroup/ci-workflows/actions/github/formatPullReque
 go 1.24.1
 
 require (
+	github.com/EncoreDigitalGroup/ci-workflows/actions/github/support v0.0.0
 	github.com/EncoreDigitalGroup/golib v0.1.1
 	github.com/google/go-github/v70 v70.0.0
 	golang.org/x/oauth2 v0.28.0
@@ -28,3 +29,5 @@ require (
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
 	golang.org/x/sys v0.30.0 // indirect
 )
+
+replace github.com/EncoreDigitalGroup/ci-workflows/actions/github/support => ../support
@@ -7,16 +7,16 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github.com/google/go-github/v70/github"
 	"golang.org/x/oauth2"
+
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/auth"
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/event"
 )
 
-const envGHToken = "GH_TOKEN"
-const envGHRepository = "GH_REPOSITORY"
-const envPRNumber = "PR_NUMBER"
 const envSourceFile = "SOURCE_FILE"
 const envDestinationFiles = "DESTINATION_FILES"
 const envRootDirectory = "ROOT_DIRECTORY"
@@ -38,9 +38,6 @@ type ValidationWarning struct {
 }
 
 func main() {
-	githubToken := os.Getenv(envGHToken)
-	repo := os.Getenv(envGHRepository)
-	prNumberStr := os.Getenv(envPRNumber)
 	sourceFile := os.Getenv(envSourceFile)
 	destinationFiles := os.Getenv(envDestinationFiles)
 	rootDirectory := os.Getenv(envRootDirectory)
@@ -54,23 +51,27 @@ func main() {
 		}
 	}
 
-	validateEnv(envGHToken, githubToken)
-	validateEnv(envGHRepository, repo)
-	validateEnv(envPRNumber, prNumberStr)
 	validateEnv(envSourceFile, destinationFiles)
 	validateEnv(envDestinationFiles, destinationFiles)
 
-	parts := strings.Split(repo, "/")
-	if len(parts) != 2 {
-		fmt.Printf("::error::%s must be in the format owner/repo\n", envGHRepository)
+	// The pull request comes from the event payload unless GH_REPOSITORY or PR_NUMBER override it
+	pullRequest, err := event.Load()
+	if err == nil {
+		err = pullRequest.Validate()
+	}
+
+	if err != nil {
+		fmt.Printf("::error::%v\n", err)
 		os.Exit(1)
 	}
-	repoOwner := parts[0]
-	repoName := parts[1]
+	repoOwner := pullRequest.Owner
+	repoName := pullRequest.Repo
+	prNumber := pullRequest.Number
 
-	prNumber, err := strconv.Atoi(prNumberStr)
+	// Authenticate with a token, or a GitHub App installation token for the repository
+	ts, err := auth.TokenSource(context.Background(), repoOwner, repoName)
 	if err != nil {
-		fmt.Printf("::error::%s is not a valid integer: %v\n", envPRNumber, err)
+		fmt.Printf("::error::%v\n", err)
 		os.Exit(1)
 	}
 
@@ -103,7 +104,7 @@ func main() {
 	}
 
 	comment := buildComment(sourceFile, newInSource, missingFromSource, warnings, rootDirectory)
-	err = postComment(githubToken, repoOwner, repoName, prNumber, comment)
+	err = postComment(ts, repoOwner, repoName, prNumber, comment)
 	if err != nil {
 		fmt.Printf("::error::Failed to post comment: %v\n", err)
 		os.Exit(1)
@@ -353,16 +354,17 @@ func buildComment(sourceFile string, newInSource []KeyDifference, missingFromSou
 	return comment.String()
 }
 
-func postComment(token, owner, repo string, prNumber int, body string) error {
+func postComment(ts oauth2.TokenSource, owner, repo string, prNumber int, body string) error {
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+	client, err := api.NewClient(ts)
+	if err != nil {
+		return err
+	}
 
 	comment := &github.IssueComment{
 		Body: &body,
 	}
 
-	_, _, err := client.Issues.CreateComment(ctx, owner, repo, prNumber, comment)
+	_, _, err = client.Issues.CreateComment(ctx, owner, repo, prNumber, comment)
 	return err
 }
@@ -0,0 +1,119 @@
+// Package api creates go-github clients for the GitHub instance a workflow runs on, which may be GitHub Enterprise Server.
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+	"golang.org/x/oauth2"
+
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/retry"
+)
+
+// EnvAPIURL is set by the Actions runner to the REST API of the instance running the workflow
+const EnvAPIURL = "GITHUB_API_URL"
+
+// Explicit inputs take precedence over GITHUB_API_URL
+const EnvBaseURL = "GH_BASE_URL"
+const EnvUploadURL = "GH_UPLOAD_URL"
+
+const DefaultBaseURL = "https://api.github.com/"
+
+// maxRateLimitWait is the longest the client waits for a rate limit to reset before giving up on the request
+const maxRateLimitWait = 2 * time.Minute
+
+// BaseURL returns the REST API URL from GH_BASE_URL or GITHUB_API_URL, falling back to api.github.com
+func BaseURL() string {
+	for _, name := range []string{EnvBaseURL, EnvAPIURL} {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+
+	return DefaultBaseURL
+}
+
+// UploadURL returns GH_UPLOAD_URL, or the upload URL that goes with the base URL. GitHub Enterprise Server serves uploads from
+// /api/uploads next to /api/v3, while GitHub.com and GHE.com serve them from the uploads host next to the api host.
+func UploadURL(baseURL string) (string, error) {
+	if value := strings.TrimSpace(os.Getenv(EnvUploadURL)); value != "" {
+		return value, nil
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid GitHub API URL %q: %v", baseURL, err)
+	}
+
+	if host, ok := strings.CutPrefix(parsed.Host, "api."); ok {
+		parsed.Host = "uploads." + host
+		parsed.Path = "/"
+		return parsed.String(), nil
+	}
+
+	path := strings.TrimSuffix(parsed.Path, "/")
+	parsed.Path = strings.TrimSuffix(path, "/api/v3") + "/api/uploads/"
+
+	return parsed.String(), nil
+}
+
+// NewClient creates a client for the configured GitHub instance that authenticates with ts and retries through NewTransport
+func NewClient(ts oauth2.TokenSource) (*github.Client, error) {
+	client := github.NewClient(&http.Client{Transport: &oauth2.Transport{Source: ts, Base: NewTransport()}})
+
+	baseURL := BaseURL()
+	if strings.TrimSuffix(baseURL, "/") == strings.TrimSuffix(DefaultBaseURL, "/") && os.Getenv(EnvUploadURL) == "" {
+		return client, nil
+	}
+
+	uploadURL, err := UploadURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err = client.WithEnterpriseURLs(baseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL %q: %v", baseURL, err)
+	}
+
+	return client, nil
+}
+
+// NewTransport returns a transport that waits out GitHub's primary and secondary rate limits, and retries gateway errors for requests
+// that are safe to repeat. The actions only PATCH fields to absolute values, so PATCH is repeated along with the idempotent methods.
+func NewTransport() http.RoundTripper {
+	transport := retry.New("GitHub")
+	transport.MaxWait = maxRateLimitWait
+	transport.Idempotent = func(method string) bool {
+		return method == http.MethodPatch || retry.IsIdempotent(method)
+	}
+
+	return transport
+}
+
+// Exhausted reports whether err is a failure the transport retries, meaning the retries ran out or could not be attempted: a rate
+// limit, a server error, or a network error. Other errors, such as a 404 or a permissions error, are answers from GitHub.
+func Exhausted(err error) bool {
+	var rateLimitError *github.RateLimitError
+	var abuseRateLimitError *github.AbuseRateLimitError
+	if errors.As(err, &rateLimitError) || errors.As(err, &abuseRateLimitError) {
+		return true
+	}
+
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) {
+		return errorResponse.Response != nil && (errorResponse.Response.StatusCode == http.StatusTooManyRequests || errorResponse.Response.StatusCode >= http.StatusInternalServerError)
+	}
+
+	var netError net.Error
+	var urlError *url.Error
+
+	return errors.As(err, &netError) || errors.As(err, &urlError)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v70/github"
+	"golang.org/x/oauth2"
+)
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseURL  string
+		apiURL   string
+		expected string
+	}{
+		{name: "default", expected: DefaultBaseURL},
+		{name: "runner API URL", apiURL: "https://github.example.com/api/v3", expected: "https://github.example.com/api/v3"},
+		{name: "explicit base URL wins", baseURL: "https://ghes.internal/api/v3/", apiURL: "https://api.github.com", expected: "https://ghes.internal/api/v3/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvBaseURL, tt.baseURL)
+			t.Setenv(EnvAPIURL, tt.apiURL)
+
+			if actual := BaseURL(); actual != tt.expected {
+				t.Errorf("BaseURL() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUploadURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		uploadURL string
+		expected  string
+	}{
+		{name: "GitHub.com", baseURL: "https://api.github.com", expected: "https://uploads.github.com/"},
+		{name: "GHE.com", baseURL: "https://api.acme.ghe.com/", expected: "https://uploads.acme.ghe.com/"},
+		{name: "Enterprise Server", baseURL: "https://github.example.com/api/v3", expected: "https://github.example.com/api/uploads/"},
+		{name: "Enterprise Server host only", baseURL: "https://github.example.com/", expected: "https://github.example.com/api/uploads/"},
+		{name: "explicit upload URL", baseURL: "https://github.example.com/api/v3", uploadURL: "https://uploads.example.com/", expected: "https://uploads.example.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvUploadURL, tt.uploadURL)
+
+			actual, err := UploadURL(tt.baseURL)
+			if err != nil {
+				t.Fatalf("UploadURL() error = %v", err)
+			}
+
+			if actual != tt.expected {
+				t.Errorf("UploadURL() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiURL         string
+		expectedBase   string
+		expectedUpload string
+	}{
+		{name: "GitHub.com", apiURL: "https://api.github.com", expectedBase: "https://api.github.com/", expectedUpload: "https://uploads.github.com/"},
+		{name: "Enterprise Server", apiURL: "https://github.example.com/api/v3", expectedBase: "https://github.example.com/api/v3/", expectedUpload: "https://github.example.com/api/uploads/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvBaseURL, "")
+			t.Setenv(EnvUploadURL, "")
+			t.Setenv(EnvAPIURL, tt.apiURL)
+
+			client, err := NewClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"}))
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			if client.BaseURL.String() != tt.expectedBase {
+				t.Errorf("BaseURL = %q, want %q", client.BaseURL, tt.expectedBase)
+			}
+
+			if client.UploadURL.String() != tt.expectedUpload {
+				t.Errorf("UploadURL = %q, want %q", client.UploadURL, tt.expectedUpload)
+			}
+		})
+	}
+}
+
+func TestExhausted(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: http.MethodPatch, URL: &url.URL{}}}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "primary rate limit", err: &github.RateLimitError{Response: response(http.StatusForbidden)}, expected: true},
+		{name: "secondary rate limit", err: &github.AbuseRateLimitError{Response: response(http.StatusForbidden)}, expected: true},
+		{name: "server error", err: &github.ErrorResponse{Response: response(http.StatusBadGateway)}, expected: true},
+		{name: "network error", err: &url.Error{Op: "Patch", URL: "https://api.github.com/", Err: errors.New("connection reset")}, expected: true},
+		{name: "wrapped", err: fmt.Errorf("failed to update pull request: %w", &github.ErrorResponse{Response: response(http.StatusServiceUnavailable)}), expected: true},
+		{name: "not found", err: &github.ErrorResponse{Response: response(http.StatusNotFound)}},
+		{name: "forbidden", err: &github.ErrorResponse{Response: response(http.StatusForbidden)}},
+		{name: "other", err: errors.New("invalid input")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Exhausted(tt.err); actual != tt.expected {
+				t.Errorf("Exhausted() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
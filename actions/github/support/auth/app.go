@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v70/github"
+	"golang.org/x/oauth2"
+
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+)
+
+// GitHub rejects app JWTs that are valid for more than ten minutes. The issue time is backdated to allow for clock drift.
+const jwtClockDrift = time.Minute
+const jwtLifetime = 9 * time.Minute
+
+// appTokenSource mints the JWTs a GitHub App authenticates with to request installation tokens
+type appTokenSource struct {
+	appID string
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+func (source *appTokenSource) Token() (*oauth2.Token, error) {
+	now := source.now()
+	expiresAt := now.Add(jwtLifetime)
+
+	jwt, err := signJWT(source.key, map[string]interface{}{
+		"iat": now.Add(-jwtClockDrift).Unix(),
+		"exp": expiresAt.Unix(),
+		"iss": source.appID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{AccessToken: jwt, TokenType: "Bearer", Expiry: expiresAt}, nil
+}
+
+// signJWT encodes the claims as a JWT signed with RS256, the only algorithm GitHub accepts for app JWTs
+func signJWT(key *rsa.PrivateKey, claims map[string]interface{}) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey reads the PEM private key GitHub generates for an app, which is PKCS #1, or the same key converted to PKCS #8.
+// Keys pasted with escaped newlines are accepted.
+func parsePrivateKey(value string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(value, `\n`, "\n")))
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %v", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+// installationTokenSource exchanges app JWTs for installation tokens that can only access the target repository
+type installationTokenSource struct {
+	ctx            context.Context
+	client         *github.Client
+	owner          string
+	repo           string
+	installationID int64
+}
+
+func newInstallationTokenSource(ctx context.Context, apiURL string, appID string, privateKey string, owner string, repo string) (*installationTokenSource, error) {
+	key, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	app := &appTokenSource{appID: appID, key: key, now: time.Now}
+	transport := &oauth2.Transport{Source: oauth2.ReuseTokenSourceWithExpiry(nil, app, time.Minute), Base: api.NewTransport()}
+	client, err := github.NewClient(&http.Client{Transport: transport}).WithEnterpriseURLs(apiURL, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub API URL %q: %v", apiURL, err)
+	}
+
+	return &installationTokenSource{ctx: ctx, client: client, owner: owner, repo: repo}, nil
+}
+
+func (source *installationTokenSource) Token() (*oauth2.Token, error) {
+	// The installation is looked up once; only the token it issues expires
+	if source.installationID == 0 {
+		installation, _, err := source.client.Apps.FindRepositoryInstallation(source.ctx, source.owner, source.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the GitHub App installation for %s/%s: %v", source.owner, source.repo, err)
+		}
+
+		source.installationID = installation.GetID()
+	}
+
+	token, _, err := source.client.Apps.CreateInstallationToken(source.ctx, source.installationID, &github.InstallationTokenOptions{
+		Repositories: []string{source.repo},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a GitHub App installation token for %s/%s: %v", source.owner, source.repo, err)
+	}
+
+	return &oauth2.Token{AccessToken: token.GetToken(), TokenType: "Bearer", Expiry: token.GetExpiresAt().Time}, nil
+}
@@ -0,0 +1,50 @@
+// Package auth provides the credentials the Go actions use to call the GitHub API: a token such as GITHUB_TOKEN, or an installation
+// token minted for a GitHub App.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/EncoreDigitalGroup/ci-workflows/actions/github/support/api"
+)
+
+const EnvToken = "GH_TOKEN"
+const EnvAppID = "GH_APP_ID"
+const EnvAppPrivateKey = "GH_APP_PRIVATE_KEY"
+
+// refreshBefore renews an installation token this long before GitHub expires it, so it does not expire between being read and used
+const refreshBefore = 5 * time.Minute
+
+// TokenSource returns an installation token source scoped to owner/repo when GH_APP_ID and GH_APP_PRIVATE_KEY are set, and GH_TOKEN
+// otherwise. Installation tokens last an hour and are minted again when they are about to expire. The app authenticates against the
+// instance api.BaseURL points to.
+func TokenSource(ctx context.Context, owner string, repo string) (oauth2.TokenSource, error) {
+	appID := strings.TrimSpace(os.Getenv(EnvAppID))
+	privateKey := strings.TrimSpace(os.Getenv(EnvAppPrivateKey))
+
+	if appID != "" || privateKey != "" {
+		if appID == "" || privateKey == "" {
+			return nil, fmt.Errorf("%s and %s must both be set to authenticate as a GitHub App", EnvAppID, EnvAppPrivateKey)
+		}
+
+		installation, err := newInstallationTokenSource(ctx, api.BaseURL(), appID, privateKey, owner, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		return oauth2.ReuseTokenSourceWithExpiry(nil, installation, refreshBefore), nil
+	}
+
+	token := os.Getenv(EnvToken)
+	if token == "" {
+		return nil, fmt.Errorf("%s is not set; set it, or %s and %s to authenticate as a GitHub App", EnvToken, EnvAppID, EnvAppPrivateKey)
+	}
+
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+}
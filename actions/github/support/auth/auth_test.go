@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func generateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return key, string(encoded)
+}
+
+// verifyJWT checks the RS256 signature and returns the claims
+func verifyJWT(t *testing.T, key *rsa.PrivateKey, jwt string) map[string]interface{} {
+	t.Helper()
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature does not verify: %v", err)
+	}
+
+	var header map[string]string
+	decodeSegment(t, parts[0], &header)
+	if header["alg"] != "RS256" {
+		t.Errorf("JWT alg = %q, want RS256", header["alg"])
+	}
+
+	var claims map[string]interface{}
+	decodeSegment(t, parts[1], &claims)
+
+	return claims
+}
+
+func decodeSegment(t *testing.T, segment string, value interface{}) {
+	t.Helper()
+
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(decoded, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAppTokenSource(t *testing.T) {
+	key, _ := generateKey(t)
+	now := time.Unix(1700000000, 0)
+
+	source := &appTokenSource{appID: "12345", key: key, now: func() time.Time { return now }}
+	token, err := source.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	claims := verifyJWT(t, key, token.AccessToken)
+	expected := map[string]interface{}{
+		"iat": float64(now.Add(-jwtClockDrift).Unix()),
+		"exp": float64(now.Add(jwtLifetime).Unix()),
+		"iss": "12345",
+	}
+
+	if !reflect.DeepEqual(claims, expected) {
+		t.Errorf("JWT claims = %v, want %v", claims, expected)
+	}
+
+	if !token.Expiry.Equal(now.Add(jwtLifetime)) {
+		t.Errorf("Token() expiry = %v, want %v", token.Expiry, now.Add(jwtLifetime))
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, pkcs1 := generateKey(t)
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8 := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}))
+
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+	}{
+		{name: "PKCS #1", value: pkcs1},
+		{name: "PKCS #8", value: pkcs8},
+		{name: "escaped newlines", value: strings.ReplaceAll(pkcs1, "\n", `\n`)},
+		{name: "not PEM", value: "not a key", expectError: true},
+		{name: "corrupt key", value: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("corrupt")})), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parsePrivateKey(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("parsePrivateKey() expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parsePrivateKey() error = %v", err)
+			}
+
+			if !parsed.Equal(key) {
+				t.Error("parsePrivateKey() returned a different key")
+			}
+		})
+	}
+}
+
+func TestInstallationTokenSource(t *testing.T) {
+	key, privateKey := generateKey(t)
+
+	var lookups, exchanges int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "Bearer ") {
+			t.Errorf("request is not authenticated with the app JWT: %q", authorization)
+		} else if claims := verifyJWT(t, key, strings.TrimPrefix(authorization, "Bearer ")); claims["iss"] != "12345" {
+			t.Errorf("JWT iss = %v, want 12345", claims["iss"])
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/acme/widgets/installation":
+			lookups++
+			w.Write([]byte(`{"id":99}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/app/installations/99/access_tokens":
+			exchanges++
+
+			var body struct {
+				Repositories []string `json:"repositories"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(body.Repositories, []string{"widgets"}) {
+				t.Errorf("token repositories = %v, want [widgets]", body.Repositories)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"token":"ghs_installation","expires_at":"2030-01-01T00:00:00Z"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source, err := newInstallationTokenSource(context.Background(), server.URL+"/api/v3/", "12345", privateKey, "acme", "widgets")
+	if err != nil {
+		t.Fatalf("newInstallationTokenSource() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+
+		if token.AccessToken != "ghs_installation" {
+			t.Errorf("Token() = %q, want ghs_installation", token.AccessToken)
+		}
+
+		if !token.Expiry.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Token() expiry = %v", token.Expiry)
+		}
+	}
+
+	if lookups != 1 || exchanges != 2 {
+		t.Errorf("lookups = %d, exchanges = %d; want 1 and 2", lookups, exchanges)
+	}
+}
+
+func TestTokenSource(t *testing.T) {
+	_, privateKey := generateKey(t)
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedToken string
+		expectError   bool
+	}{
+		{name: "token", env: map[string]string{EnvToken: "ghp_token"}, expectedToken: "ghp_token"},
+		{name: "app without a private key", env: map[string]string{EnvToken: "ghp_token", EnvAppID: "12345"}, expectError: true},
+		{name: "private key without an app", env: map[string]string{EnvAppPrivateKey: privateKey}, expectError: true},
+		{name: "invalid private key", env: map[string]string{EnvAppID: "12345", EnvAppPrivateKey: "not a key"}, expectError: true},
+		{name: "no credentials", env: map[string]string{}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{EnvToken, EnvAppID, EnvAppPrivateKey} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			source, err := TokenSource(context.Background(), "acme", "widgets")
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("TokenSource() expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("TokenSource() error = %v", err)
+			}
+
+			token, err := source.Token()
+			if err != nil {
+				t.Fatalf("Token() error = %v", err)
+			}
+
+			if token.AccessToken != tt.expectedToken {
+				t.Errorf("Token() = %q, want %q", token.AccessToken, tt.expectedToken)
+			}
+		})
+	}
+}
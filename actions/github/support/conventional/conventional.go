@@ -0,0 +1,58 @@
+// Package conventional formats pull request titles as Conventional Commits, such as feat(PROJ-12): add export.
+package conventional
+
+import (
+	"fmt"
+	"strings"
+)
+
+// commitTypes are the Conventional Commit types a branch type may already be named after
+var commitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// Type returns the commit type for a branch type such as feature or bugfix. Overrides are comma-separated branchType:commitType pairs,
+// where "*" sets the type used for branch types without a mapping. A type ending in "!" marks a breaking change.
+func Type(branchType string, overrides string) string {
+	types := map[string]string{
+		"epic":    "feat",
+		"feature": "feat",
+		"bugfix":  "fix",
+		"hotfix":  "fix!",
+	}
+
+	for _, commitType := range commitTypes {
+		types[commitType] = commitType
+	}
+
+	// Branch types without a mapping fall back to the "*" entry
+	types["*"] = "chore"
+
+	if overrides != "" {
+		pairs := strings.Split(overrides, ",")
+		for _, pair := range pairs {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) == 2 {
+				key := strings.ToLower(strings.TrimSpace(kv[0]))
+				value := strings.TrimSpace(kv[1])
+				types[key] = value
+			}
+		}
+	}
+
+	if commitType, ok := types[strings.ToLower(branchType)]; ok && branchType != "" {
+		return commitType
+	}
+
+	return types["*"]
+}
+
+// Title joins the commit type, the issue keys as the scope, and the subject
+func Title(commitType string, scopes []string, subject string) string {
+	// A breaking change marker belongs after the scope, e.g. fix(PROJ-12)!: subject
+	breaking := ""
+	if strings.HasSuffix(commitType, "!") {
+		commitType = strings.TrimSuffix(commitType, "!")
+		breaking = "!"
+	}
+
+	return fmt.Sprintf("%s(%s)%s: %s", commitType, strings.Join(scopes, ","), breaking, subject)
+}
@@ -0,0 +1,49 @@
+package conventional
+
+import "testing"
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		name       string
+		branchType string
+		overrides  string
+		expected   string
+	}{
+		{name: "feature", branchType: "feature", expected: "feat"},
+		{name: "hotfix is breaking", branchType: "hotfix", expected: "fix!"},
+		{name: "branch named after a commit type", branchType: "docs", expected: "docs"},
+		{name: "case insensitive", branchType: "Bugfix", expected: "fix"},
+		{name: "unmapped falls back", branchType: "spike", expected: "chore"},
+		{name: "no branch type falls back", expected: "chore"},
+		{name: "override", branchType: "spike", overrides: "spike:test", expected: "test"},
+		{name: "override fallback", branchType: "spike", overrides: " * : build ", expected: "build"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Type(tt.branchType, tt.overrides); actual != tt.expected {
+				t.Errorf("Type() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := []struct {
+		commitType string
+		scopes     []string
+		expected   string
+	}{
+		{commitType: "feat", scopes: []string{"PROJ-12"}, expected: "feat(PROJ-12): add export"},
+		{commitType: "fix!", scopes: []string{"PROJ-12"}, expected: "fix(PROJ-12)!: add export"},
+		{commitType: "feat", scopes: []string{"PROJ-12", "PROJ-15"}, expected: "feat(PROJ-12,PROJ-15): add export"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if actual := Title(tt.commitType, tt.scopes, "add export"); actual != tt.expected {
+				t.Errorf("Title() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package event reads the pull request a workflow run acts on from the webhook payload GitHub writes to GITHUB_EVENT_PATH.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v70/github"
+)
+
+const EnvEventName = "GITHUB_EVENT_NAME"
+const EnvEventPath = "GITHUB_EVENT_PATH"
+const EnvRunAttempt = "GITHUB_RUN_ATTEMPT"
+const EnvGitHubRepository = "GITHUB_REPOSITORY"
+
+// Explicit inputs override the values read from the payload
+const EnvRepository = "GH_REPOSITORY"
+const EnvPRNumber = "PR_NUMBER"
+const EnvBranchName = "BRANCH_NAME"
+
+// regexMergeQueueRef matches merge queue branches such as refs/heads/gh-readonly-queue/main/pr-42-<sha>
+var regexMergeQueueRef = regexp.MustCompile(`gh-readonly-queue/.+/pr-([0-9]+)-[0-9a-f]+$`)
+
+// PullRequest describes the pull request a workflow run acts on
+type PullRequest struct {
+	Name    string
+	Action  string
+	Owner   string
+	Repo    string
+	Number  int
+	HeadRef string
+	Title   string
+	Body    string
+	Merged  bool
+
+	// Payload is the pull request object from a pull_request or pull_request_target payload. It is left empty on re-runs, since they
+	// replay the original payload and its title and body may have changed since.
+	Payload *github.PullRequest
+}
+
+// payload holds the parts of the supported webhook payloads that identify the pull request
+type payload struct {
+	Action      string              `json:"action"`
+	PullRequest *github.PullRequest `json:"pull_request"`
+	Issue       *github.Issue       `json:"issue"`
+	MergeGroup  *struct {
+		HeadRef string `json:"head_ref"`
+	} `json:"merge_group"`
+	Repository *github.Repository `json:"repository"`
+}
+
+// Load reads the event payload, when there is one, and applies GH_REPOSITORY, PR_NUMBER, and BRANCH_NAME on top of it. It does not
+// require a pull request to be found; call Validate for that.
+func Load() (*PullRequest, error) {
+	pullRequest := &PullRequest{Name: os.Getenv(EnvEventName)}
+
+	if eventPath := os.Getenv(EnvEventPath); eventPath != "" {
+		content, err := os.ReadFile(eventPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event payload: %v", err)
+		}
+
+		var event payload
+		if err := json.Unmarshal(content, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse event payload: %v", err)
+		}
+
+		pullRequest.fromPayload(event, replayed())
+	}
+
+	if pullRequest.Owner == "" {
+		pullRequest.Owner, pullRequest.Repo, _ = strings.Cut(os.Getenv(EnvGitHubRepository), "/")
+	}
+
+	if err := pullRequest.applyOverrides(); err != nil {
+		return nil, err
+	}
+
+	return pullRequest, nil
+}
+
+// replayed reports whether this is a re-run, which is given the payload of the first attempt
+func replayed() bool {
+	attempt, err := strconv.Atoi(os.Getenv(EnvRunAttempt))
+	return err == nil && attempt > 1
+}
+
+func (pullRequest *PullRequest) fromPayload(event payload, replayed bool) {
+	pullRequest.Action = event.Action
+	if event.Repository != nil {
+		pullRequest.Owner = event.Repository.GetOwner().GetLogin()
+		pullRequest.Repo = event.Repository.GetName()
+	}
+
+	switch {
+	case event.PullRequest != nil:
+		pullRequest.Number = event.PullRequest.GetNumber()
+		pullRequest.HeadRef = event.PullRequest.GetHead().GetRef()
+		pullRequest.Merged = event.PullRequest.GetMerged()
+		if !replayed {
+			pullRequest.Title = event.PullRequest.GetTitle()
+			pullRequest.Body = event.PullRequest.GetBody()
+			pullRequest.Payload = event.PullRequest
+		}
+	case event.MergeGroup != nil:
+		// The merge queue names its branches after the pull request, which is the only place the payload refers to it
+		if matches := regexMergeQueueRef.FindStringSubmatch(event.MergeGroup.HeadRef); matches != nil {
+			pullRequest.Number, _ = strconv.Atoi(matches[1])
+		}
+	case event.Issue != nil && event.Issue.IsPullRequest():
+		pullRequest.Number = event.Issue.GetNumber()
+		if !replayed {
+			pullRequest.Title = event.Issue.GetTitle()
+			pullRequest.Body = event.Issue.GetBody()
+		}
+	}
+}
+
+// applyOverrides replaces values read from the payload with the explicit inputs. An override naming another pull request drops the
+// head ref, title, body, and payload, since they describe the pull request that triggered the run.
+func (pullRequest *PullRequest) applyOverrides() error {
+	if repository := strings.TrimSpace(os.Getenv(EnvRepository)); repository != "" {
+		owner, repo, ok := strings.Cut(repository, "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return fmt.Errorf("%s must be in the format owner/repo", EnvRepository)
+		}
+
+		if !strings.EqualFold(owner, pullRequest.Owner) || !strings.EqualFold(repo, pullRequest.Repo) {
+			pullRequest.forgetPayload()
+		}
+
+		pullRequest.Owner = owner
+		pullRequest.Repo = repo
+	}
+
+	if number := strings.TrimSpace(os.Getenv(EnvPRNumber)); number != "" {
+		prNumber, err := strconv.Atoi(number)
+		if err != nil {
+			return fmt.Errorf("%s is not a valid integer: %v", EnvPRNumber, err)
+		}
+
+		if prNumber != pullRequest.Number {
+			pullRequest.forgetPayload()
+		}
+
+		pullRequest.Number = prNumber
+	}
+
+	if branchName := strings.TrimSpace(os.Getenv(EnvBranchName)); branchName != "" {
+		pullRequest.HeadRef = branchName
+	}
+
+	return nil
+}
+
+func (pullRequest *PullRequest) forgetPayload() {
+	pullRequest.HeadRef = ""
+	pullRequest.Title = ""
+	pullRequest.Body = ""
+	pullRequest.Merged = false
+	pullRequest.Payload = nil
+}
+
+// Validate reports an error when neither the payload nor the explicit inputs identify the repository and pull request
+func (pullRequest *PullRequest) Validate() error {
+	if pullRequest.Owner == "" || pullRequest.Repo == "" {
+		return fmt.Errorf("unable to determine the repository; set %s", EnvRepository)
+	}
+
+	if pullRequest.Number <= 0 {
+		return fmt.Errorf("unable to determine the pull request from the %s event; set %s", pullRequest.eventName(), EnvPRNumber)
+	}
+
+	return nil
+}
+
+func (pullRequest *PullRequest) eventName() string {
+	if pullRequest.Name == "" {
+		return "current"
+	}
+
+	return pullRequest.Name
+}
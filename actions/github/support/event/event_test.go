@@ -0,0 +1,170 @@
+package event
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePayload(t *testing.T, content string) {
+	t.Helper()
+
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(eventPath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvEventPath, eventPath)
+}
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{EnvEventName, EnvEventPath, EnvRunAttempt, EnvGitHubRepository, EnvRepository, EnvPRNumber, EnvBranchName} {
+		t.Setenv(name, "")
+	}
+}
+
+const repositoryPayload = `"repository":{"name":"widgets","owner":{"login":"acme"}}`
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		env         map[string]string
+		expected    PullRequest
+		hasPayload  bool
+		expectError bool
+	}{
+		{
+			name:       "pull_request",
+			payload:    `{"action":"opened","pull_request":{"number":42,"title":"Add widgets","body":"Details","head":{"ref":"feature/PROJ-1-add-widgets"}},` + repositoryPayload + `}`,
+			env:        map[string]string{EnvEventName: "pull_request"},
+			expected:   PullRequest{Name: "pull_request", Action: "opened", Owner: "acme", Repo: "widgets", Number: 42, HeadRef: "feature/PROJ-1-add-widgets", Title: "Add widgets", Body: "Details"},
+			hasPayload: true,
+		},
+		{
+			name:       "merged pull_request_target",
+			payload:    `{"action":"closed","pull_request":{"number":7,"merged":true,"head":{"ref":"PROJ-2-fix"}},` + repositoryPayload + `}`,
+			env:        map[string]string{EnvEventName: "pull_request_target"},
+			expected:   PullRequest{Name: "pull_request_target", Action: "closed", Owner: "acme", Repo: "widgets", Number: 7, HeadRef: "PROJ-2-fix", Merged: true},
+			hasPayload: true,
+		},
+		{
+			name:     "re-run drops the title and body",
+			payload:  `{"action":"opened","pull_request":{"number":42,"title":"Add widgets","head":{"ref":"PROJ-1-add-widgets"}},` + repositoryPayload + `}`,
+			env:      map[string]string{EnvRunAttempt: "2"},
+			expected: PullRequest{Action: "opened", Owner: "acme", Repo: "widgets", Number: 42, HeadRef: "PROJ-1-add-widgets"},
+		},
+		{
+			name:     "merge_group",
+			payload:  `{"action":"checks_requested","merge_group":{"head_ref":"refs/heads/gh-readonly-queue/main/pr-15-4f6e2ab0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6"},` + repositoryPayload + `}`,
+			env:      map[string]string{EnvEventName: "merge_group"},
+			expected: PullRequest{Name: "merge_group", Action: "checks_requested", Owner: "acme", Repo: "widgets", Number: 15},
+		},
+		{
+			name:     "issue_comment on a pull request",
+			payload:  `{"action":"created","issue":{"number":9,"title":"Add widgets","body":"Details","pull_request":{"url":"https://api.github.com/repos/acme/widgets/pulls/9"}},` + repositoryPayload + `}`,
+			env:      map[string]string{EnvEventName: "issue_comment"},
+			expected: PullRequest{Name: "issue_comment", Action: "created", Owner: "acme", Repo: "widgets", Number: 9, Title: "Add widgets", Body: "Details"},
+		},
+		{
+			name:     "issue_comment on an issue",
+			payload:  `{"action":"created","issue":{"number":9,"title":"Bug"},` + repositoryPayload + `}`,
+			expected: PullRequest{Action: "created", Owner: "acme", Repo: "widgets"},
+		},
+		{
+			name:     "overrides without a payload",
+			env:      map[string]string{EnvRepository: "acme/widgets", EnvPRNumber: "3", EnvBranchName: "PROJ-3-docs"},
+			expected: PullRequest{Owner: "acme", Repo: "widgets", Number: 3, HeadRef: "PROJ-3-docs"},
+		},
+		{
+			name:       "overrides naming the same pull request keep the payload",
+			payload:    `{"action":"edited","pull_request":{"number":42,"title":"Add widgets","head":{"ref":"PROJ-1-add-widgets"}},` + repositoryPayload + `}`,
+			env:        map[string]string{EnvRepository: "Acme/Widgets", EnvPRNumber: "42"},
+			expected:   PullRequest{Action: "edited", Owner: "Acme", Repo: "Widgets", Number: 42, HeadRef: "PROJ-1-add-widgets", Title: "Add widgets"},
+			hasPayload: true,
+		},
+		{
+			name:     "overrides naming another pull request drop the payload",
+			payload:  `{"action":"edited","pull_request":{"number":42,"title":"Add widgets","head":{"ref":"PROJ-1-add-widgets"}},` + repositoryPayload + `}`,
+			env:      map[string]string{EnvPRNumber: "43"},
+			expected: PullRequest{Action: "edited", Owner: "acme", Repo: "widgets", Number: 43},
+		},
+		{
+			name:     "repository falls back to GITHUB_REPOSITORY",
+			env:      map[string]string{EnvGitHubRepository: "acme/widgets", EnvPRNumber: "5"},
+			expected: PullRequest{Owner: "acme", Repo: "widgets", Number: 5},
+		},
+		{
+			name:        "invalid repository override",
+			env:         map[string]string{EnvRepository: "widgets"},
+			expectError: true,
+		},
+		{
+			name:        "invalid number override",
+			env:         map[string]string{EnvPRNumber: "forty-two"},
+			expectError: true,
+		},
+		{
+			name:        "invalid payload",
+			payload:     `{"action":`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			if tt.payload != "" {
+				writePayload(t, tt.payload)
+			}
+
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			actual, err := Load()
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Load() expected an error")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if (actual.Payload != nil) != tt.hasPayload {
+				t.Errorf("Load() Payload = %v, want present %v", actual.Payload, tt.hasPayload)
+			}
+
+			actual.Payload = nil
+			if *actual != tt.expected {
+				t.Errorf("Load() = %+v, want %+v", *actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		pullRequest PullRequest
+		expectError bool
+	}{
+		{name: "complete", pullRequest: PullRequest{Owner: "acme", Repo: "widgets", Number: 1}},
+		{name: "missing repository", pullRequest: PullRequest{Number: 1}, expectError: true},
+		{name: "missing number", pullRequest: PullRequest{Name: "merge_group", Owner: "acme", Repo: "widgets"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.pullRequest.Validate(); (err != nil) != tt.expectError {
+				t.Errorf("Validate() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Package issuename formats the issue name taken from a branch, such as add-api-export, for a pull request title.
+package issuename
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Format turns a branch-style name such as add-api-export into title case, or lower case for Conventional Commit subjects.
+// Exceptions are comma-separated word:replacement pairs, such as "Graphql:GraphQL", that add to or replace the built-in exceptions.
+func Format(issueName string, exceptions string, lowerCase bool) string {
+	// Replace hyphens with spaces and capitalize each word
+	formattedIssueName := strings.ReplaceAll(issueName, "-", " ")
+	titleCaser := cases.Title(language.English)
+	formattedIssueName = titleCaser.String(formattedIssueName)
+
+	if lowerCase {
+		formattedIssueName = strings.ToLower(formattedIssueName)
+	}
+
+	defaultExceptions := map[string]string{
+		"Api":          "API",
+		"Css":          "CSS",
+		"Db":           "DB",
+		"Html":         "HTML",
+		"Rest":         "REST",
+		"Rockrms":      "RockRMS",
+		"Mpc":          "MPC",
+		"Myportal":     "MyPortal",
+		"Pco":          "PCO",
+		"Php":          "PHP",
+		"Phpstan":      "PHPStan",
+		"Servicepoint": "ServicePoint",
+		"Themekit":     "ThemeKit",
+		"Uri":          "URI",
+		"Webcms":       "WebCMS",
+		"Webui":        "WebUI",
+	}
+
+	if exceptions != "" {
+		pairs := strings.Split(exceptions, ",")
+		for _, pair := range pairs {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) == 2 {
+				key := strings.TrimSpace(kv[0])
+				value := strings.TrimSpace(kv[1])
+				defaultExceptions[key] = value
+			}
+		}
+	}
+
+	words := strings.Fields(formattedIssueName)
+	for i, word := range words {
+		if val, ok := defaultExceptions[word]; ok {
+			words[i] = val
+		} else if val, ok := defaultExceptions[titleCaser.String(word)]; ok {
+			words[i] = val
+		}
+	}
+
+	return strings.Join(words, " ")
+}
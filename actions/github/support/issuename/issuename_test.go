@@ -0,0 +1,27 @@
+package issuename
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		issueName  string
+		exceptions string
+		lowerCase  bool
+		expected   string
+	}{
+		{name: "title case", issueName: "add-export-endpoint", expected: "Add Export Endpoint"},
+		{name: "built-in exception", issueName: "add-api-docs", expected: "Add API Docs"},
+		{name: "lower case keeps exceptions", issueName: "add-api-docs", lowerCase: true, expected: "add API docs"},
+		{name: "custom exception", issueName: "graphql-schema", exceptions: "Graphql:GraphQL", expected: "GraphQL Schema"},
+		{name: "custom exception replaces built-in", issueName: "api-client", exceptions: " Api : Api ", expected: "Api Client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := Format(tt.issueName, tt.exceptions, tt.lowerCase); actual != tt.expected {
+				t.Errorf("Format() = %q, want %q", actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Package retry provides an HTTP transport that retries requests an API rejected for rate limiting, and idempotent requests that failed
+// with a gateway or availability error.
+package retry
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EncoreDigitalGroup/golib/logger"
+)
+
+// secondaryLimitWait is how long GitHub asks clients to wait after a secondary rate limit that does not say when to retry
+const secondaryLimitWait = time.Minute
+
+// Transport waits for Retry-After or the rate limit reset when the API sends one, and backs off exponentially otherwise. When the
+// retries run out, the last response is returned so the caller sees the API's error.
+type Transport struct {
+	Base           http.RoundTripper
+	Name           string
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxWait caps how long the API may ask the transport to wait before a retry; a longer wait is not retried. Zero leaves the wait
+	// bounded only by the request's deadline.
+	MaxWait time.Duration
+
+	// Idempotent reports whether a request may be sent again after a gateway error, which may have reached the API. It defaults to
+	// IsIdempotent.
+	Idempotent func(method string) bool
+}
+
+// New returns a transport that names the API in its log messages, such as "Jira" or "GitHub"
+func New(name string) *Transport {
+	return &Transport{
+		Base:           http.DefaultTransport,
+		Name:           name,
+		MaxRetries:     3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := t.base().RoundTrip(request)
+		if attempt >= t.MaxRetries {
+			return response, err
+		}
+
+		delay, retry := t.shouldRetry(request, response, err, attempt)
+		if !retry {
+			return response, err
+		}
+
+		// A request body can only be sent again when it can be rebuilt
+		if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+			return response, err
+		}
+
+		if t.MaxWait > 0 && delay > t.MaxWait {
+			return response, err
+		}
+
+		if deadline, ok := request.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return response, err
+		}
+
+		if response != nil {
+			logger.Infof("%s returned %s for %s %s; retrying in %s", t.Name, response.Status, request.Method, request.URL.Path, delay)
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		} else {
+			logger.Infof("%s request %s %s failed: %v; retrying in %s", t.Name, request.Method, request.URL.Path, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+
+		if request.GetBody != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			request = request.Clone(request.Context())
+			request.Body = body
+		}
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+
+	return t.Base
+}
+
+func (t *Transport) idempotent(method string) bool {
+	if t.Idempotent == nil {
+		return IsIdempotent(method)
+	}
+
+	return t.Idempotent(method)
+}
+
+// shouldRetry reports whether the request should be sent again, and how long to wait first
+func (t *Transport) shouldRetry(request *http.Request, response *http.Response, err error, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return t.backoff(attempt), request.Context().Err() == nil && t.idempotent(request.Method)
+	}
+
+	// Rate-limited requests are refused before the API acts on them, so they are safe to send again whatever the method
+	if delay, ok := rateLimitDelay(response); ok {
+		if delay < 0 {
+			delay = t.backoff(attempt)
+		}
+
+		return delay, true
+	}
+
+	switch response.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return t.backoff(attempt), t.idempotent(request.Method)
+	}
+
+	return 0, false
+}
+
+// rateLimitDelay reports whether the response refused the request for rate limiting, with the wait the API asked for. A negative delay
+// means the API did not say how long to wait.
+func rateLimitDelay(response *http.Response) (time.Duration, bool) {
+	if response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+
+	if delay, ok := RetryAfter(response.Header.Get("Retry-After")); ok {
+		return delay, true
+	}
+
+	// GitHub reports an exhausted primary rate limit with a zero remaining count and the time it resets, in Unix seconds
+	if response.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(response.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Until(time.Unix(reset, 0)), 0), true
+		}
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		return -1, true
+	}
+
+	// A 403 is usually a permissions error; GitHub only says it is a secondary rate limit in the message
+	if secondaryRateLimit(response) {
+		return secondaryLimitWait, true
+	}
+
+	return 0, false
+}
+
+// secondaryRateLimit reads the response body to check for GitHub's secondary rate limit message, leaving the body readable
+func secondaryRateLimit(response *http.Response) bool {
+	if response.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// IsIdempotent reports whether requests with the method can be repeated without changing the result
+func IsIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// backoff doubles the delay on each attempt up to MaxBackoff
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.InitialBackoff << attempt
+	if delay > t.MaxBackoff || delay <= 0 {
+		return t.MaxBackoff
+	}
+
+	return delay
+}
+
+// RetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func RetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testClient() *http.Client {
+	return &http.Client{Transport: &Transport{
+		Base:           http.DefaultTransport,
+		Name:           "Test",
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		MaxWait:        time.Second,
+	}}
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		statuses         []int
+		headers          map[string]string
+		body             string
+		expectedStatus   int
+		expectedAttempts int
+	}{
+		{name: "rate limited then succeeds", method: http.MethodGet, statuses: []int{429, 429, 200}, headers: map[string]string{"Retry-After": "0"}, expectedStatus: 200, expectedAttempts: 3},
+		{name: "rate limited post is retried", method: http.MethodPost, statuses: []int{429, 201}, headers: map[string]string{"Retry-After": "0"}, expectedStatus: 201, expectedAttempts: 2},
+		{name: "unavailable get is retried", method: http.MethodGet, statuses: []int{503, 200}, expectedStatus: 200, expectedAttempts: 2},
+		{name: "unavailable post is not retried", method: http.MethodPost, statuses: []int{503, 201}, expectedStatus: 503, expectedAttempts: 1},
+		{name: "client errors are not retried", method: http.MethodGet, statuses: []int{404, 200}, expectedStatus: 404, expectedAttempts: 1},
+		{name: "retries are bounded", method: http.MethodGet, statuses: []int{429, 429, 429, 429, 429, 200}, expectedStatus: 429, expectedAttempts: 4},
+		{name: "exhausted primary rate limit waits for the reset", method: http.MethodPatch, statuses: []int{403, 200}, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "0"}, expectedStatus: 200, expectedAttempts: 2},
+		{name: "secondary rate limit with Retry-After", method: http.MethodPost, statuses: []int{403, 201}, headers: map[string]string{"Retry-After": "0"}, expectedStatus: 201, expectedAttempts: 2},
+		{name: "forbidden is not retried", method: http.MethodGet, statuses: []int{403, 200}, headers: map[string]string{"X-RateLimit-Remaining": "4999"}, body: `{"message":"Resource not accessible by integration"}`, expectedStatus: 403, expectedAttempts: 1},
+		{name: "reset beyond the longest wait is not retried", method: http.MethodGet, statuses: []int{403, 200}, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}, expectedStatus: 403, expectedAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if body, _ := io.ReadAll(r.Body); r.Method != http.MethodGet && string(body) != `{"body":"payload"}` {
+					t.Errorf("attempt %d sent body %q", attempts+1, body)
+				}
+
+				for name, value := range tt.headers {
+					w.Header().Set(name, value)
+				}
+
+				w.WriteHeader(tt.statuses[attempts])
+				w.Write([]byte(tt.body))
+				attempts++
+			}))
+			defer server.Close()
+
+			request, _ := http.NewRequest(tt.method, server.URL, strings.NewReader(`{"body":"payload"}`))
+			response, err := testClient().Do(request)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.expectedStatus || attempts != tt.expectedAttempts {
+				t.Errorf("status = %d after %d attempts, want %d after %d", response.StatusCode, attempts, tt.expectedStatus, tt.expectedAttempts)
+			}
+
+			if body, _ := io.ReadAll(response.Body); string(body) != tt.body {
+				t.Errorf("response body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestTransportIdempotent(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	transport := testClient().Transport.(*Transport)
+	transport.Idempotent = func(method string) bool { return method == http.MethodPatch }
+
+	request, _ := http.NewRequest(http.MethodPatch, server.URL, strings.NewReader(`{"title":"Title"}`))
+	response, err := (&http.Client{Transport: transport}).Do(request)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	response.Body.Close()
+
+	if attempts != 4 {
+		t.Errorf("attempts = %d, want 4 for a method the transport was told is idempotent", attempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if delay, ok := RetryAfter("7"); !ok || delay != 7*time.Second {
+		t.Errorf("RetryAfter(\"7\") = %v, %v", delay, ok)
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := RetryAfter(future); !ok || delay <= 0 || delay > time.Minute {
+		t.Errorf("RetryAfter(%q) = %v, %v", future, delay, ok)
+	}
+
+	if _, ok := RetryAfter("soon"); ok {
+		t.Error("RetryAfter() accepted an invalid value")
+	}
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second))
+	defer cancel()
+
+	transport := testClient().Transport.(*Transport)
+	transport.MaxWait = 0
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	response, err := (&http.Client{Transport: transport}).Do(request)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusTooManyRequests || attempts != 1 {
+		t.Errorf("status = %d after %d attempts; a Retry-After beyond the deadline should not be waited for", response.StatusCode, attempts)
+	}
+}